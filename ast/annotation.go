@@ -0,0 +1,82 @@
+package ast
+
+import (
+	"monkey/token"
+	"strings"
+)
+
+// TypeExpression is a type annotation: int, [int], {string: int}, or
+// fn(int, string) -> bool
+type TypeExpression interface {
+	Node
+}
+
+// NamedType is a type by its name: int
+type NamedType struct {
+	Token token.Token
+	Name  string
+}
+
+// TokenLiteral allows nt to be an AST node
+func (nt NamedType) TokenLiteral() string {
+	return nt.Token.Literal
+}
+
+// String returns the type's name
+func (nt NamedType) String() string {
+	return nt.Name
+}
+
+// ArrayType is a list of Elem: [int]
+type ArrayType struct {
+	Token token.Token // the [
+	Elem  TypeExpression
+}
+
+// TokenLiteral allows at to be an AST node
+func (at ArrayType) TokenLiteral() string {
+	return at.Token.Literal
+}
+
+// String returns the element type in brackets
+func (at ArrayType) String() string {
+	return "[" + at.Elem.String() + "]"
+}
+
+// HashType maps Key to Value: {string: int}
+type HashType struct {
+	Token token.Token // the {
+	Key   TypeExpression
+	Value TypeExpression
+}
+
+// TokenLiteral allows ht to be an AST node
+func (ht HashType) TokenLiteral() string {
+	return ht.Token.Literal
+}
+
+// String returns key, and value types in braces
+func (ht HashType) String() string {
+	return "{" + ht.Key.String() + ": " + ht.Value.String() + "}"
+}
+
+// FuncType is a function: fn(int, string) -> bool
+type FuncType struct {
+	Token  token.Token // the fn
+	Params []TypeExpression
+	Result TypeExpression
+}
+
+// TokenLiteral allows ft to be an AST node
+func (ft FuncType) TokenLiteral() string {
+	return ft.Token.Literal
+}
+
+// String returns parameter, and result types
+func (ft FuncType) String() string {
+	var params []string
+	for _, p := range ft.Params {
+		params = append(params, p.String())
+	}
+	return ft.Token.Literal + "(" + strings.Join(params, ", ") + ") -> " + ft.Result.String()
+}
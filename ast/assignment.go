@@ -3,9 +3,12 @@ package ast
 import "monkey/token"
 
 // LetStatement can be the following block: let foo = 4
+// or, binding a variable that can't be reassigned: const foo = 4
+// The variable's type can be annotated: let foo: int = 4
 type LetStatement struct {
 	Token token.Token
 	Name  *Identifier
+	Type  TypeExpression // nil without an annotation
 	Value Expression
 }
 
@@ -14,12 +17,20 @@ func (ls LetStatement) TokenLiteral() string {
 	return ls.Token.Literal
 }
 
+// Const is true if ls' variable can't be reassigned
+func (ls LetStatement) Const() bool {
+	return ls.Token.Type == token.CONST
+}
+
 // String returns token, and literal value
 func (ls LetStatement) String() string {
 	str := ls.Token.Literal
 	if ls.Name != nil {
 		str += " " + ls.Name.Value
 	}
+	if ls.Type != nil {
+		str += ": " + ls.Type.String()
+	}
 
 	if ls.Value != nil {
 		str += " = " + ls.Value.String() + ";"
@@ -27,3 +38,26 @@ func (ls LetStatement) String() string {
 
 	return str
 }
+
+// AssignExpression updates a variable: foo = 4, or foo += 4
+type AssignExpression struct {
+	Token    token.Token // the operator
+	Name     *Identifier
+	Operator string
+	Value    Expression
+}
+
+// TokenLiteral allows ae to be an AST node
+func (ae AssignExpression) TokenLiteral() string {
+	return ae.Token.Literal
+}
+
+// String returns variable, operator, and value
+func (ae AssignExpression) String() string {
+	str := ae.Name.String() + " " + ae.Operator
+	if ae.Value != nil {
+		str += " " + ae.Value.String()
+	}
+
+	return str
+}
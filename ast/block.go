@@ -0,0 +1,26 @@
+package ast
+
+import (
+	"monkey/token"
+	"strings"
+)
+
+// BlockStatement is statements between braces: { let foo = 4; foo }
+type BlockStatement struct {
+	Token      token.Token
+	Statements []Statement
+}
+
+// TokenLiteral allows bs to be an AST node
+func (bs BlockStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+// String returns the block's statements, between braces
+func (bs BlockStatement) String() string {
+	var ss []string
+	for _, s := range bs.Statements {
+		ss = append(ss, s.String())
+	}
+	return "{ " + strings.Join(ss, " ") + " }"
+}
@@ -0,0 +1,72 @@
+package ast
+
+import (
+	"monkey/token"
+	"strings"
+)
+
+// SpawnExpression runs a call concurrently: spawn f(x). A function literal
+// is called without arguments: spawn fn() { }
+type SpawnExpression struct {
+	Token token.Token
+	Call  Expression // a CallExpression, or FunctionLiteral
+}
+
+// TokenLiteral allows se to be an AST node
+func (se SpawnExpression) TokenLiteral() string {
+	return se.Token.Literal
+}
+
+// String returns token, and the spawned call
+func (se SpawnExpression) String() string {
+	return se.Token.Literal + " " + se.Call.String()
+}
+
+// SelectStatement runs the first case whose channel operation can go ahead,
+// or Default if none can:
+// select { case v = recv(ch) { } case send(out, 1) { } default { } }
+type SelectStatement struct {
+	Token   token.Token
+	Cases   []*SelectCase
+	Default *BlockStatement // nil without a default case
+}
+
+// TokenLiteral allows ss to be an AST node
+func (ss SelectStatement) TokenLiteral() string {
+	return ss.Token.Literal
+}
+
+// String returns the cases, in braces
+func (ss SelectStatement) String() string {
+	var cases []string
+	for _, c := range ss.Cases {
+		cases = append(cases, c.String())
+	}
+	if ss.Default != nil {
+		cases = append(cases, "default "+ss.Default.String())
+	}
+	return ss.Token.Literal + " { " + strings.Join(cases, " ") + " }"
+}
+
+// SelectCase is a channel operation in a select, and the block it runs:
+// case v = recv(ch) { }
+type SelectCase struct {
+	Token     token.Token
+	Name      *Identifier // bound to the operation's value in Body, or nil
+	Operation *CallExpression
+	Body      *BlockStatement
+}
+
+// TokenLiteral allows sc to be an AST node
+func (sc SelectCase) TokenLiteral() string {
+	return sc.Token.Literal
+}
+
+// String returns the operation, and body
+func (sc SelectCase) String() string {
+	str := sc.Token.Literal + " "
+	if sc.Name != nil {
+		str += sc.Name.String() + " = "
+	}
+	return str + sc.Operation.String() + " " + sc.Body.String()
+}
@@ -30,6 +30,12 @@ func (es ExpressionStatement) String() string {
 type Identifier struct {
 	Token token.Token
 	Value string
+
+	// Depth is how many scopes out the variable was bound, and Slot is its
+	// index in that scope. Both are set by the resolver, Depth is -1 when
+	// the variable is undefined.
+	Depth int
+	Slot  int
 }
 
 // TokenLiteral allows i to be an AST node
@@ -37,9 +43,9 @@ func (i Identifier) TokenLiteral() string {
 	return i.Token.Literal
 }
 
-// String returns token, and literal value
+// String returns the variable's name
 func (i Identifier) String() string {
-	return i.Token.Literal
+	return i.Value
 }
 
 // Integer contains a number
@@ -79,3 +85,79 @@ func (pe PrefixExpression) String() string {
 
 	return str
 }
+
+// InfixExpression is an operator between two operands: 4 * 5
+type InfixExpression struct {
+	Token    token.Token // the operator
+	Left     Expression
+	Operator string
+	Right    Expression
+}
+
+// TokenLiteral allows ie to be an AST node
+func (ie InfixExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+// String returns the operands, and operator, in parentheses
+func (ie InfixExpression) String() string {
+	return "(" + ie.Left.String() + " " + ie.Operator + " " + ie.Right.String() + ")"
+}
+
+// Boolean is true, or false
+type Boolean struct {
+	Token token.Token
+	Value bool
+}
+
+// TokenLiteral allows b to be an AST node
+func (b Boolean) TokenLiteral() string {
+	return b.Token.Literal
+}
+
+// String returns token's literal value
+func (b Boolean) String() string {
+	return b.Token.Literal
+}
+
+// StringLiteral contains text between quotes
+type StringLiteral struct {
+	Token token.Token
+	Value string
+}
+
+// TokenLiteral allows sl to be an AST node
+func (sl StringLiteral) TokenLiteral() string {
+	return sl.Token.Literal
+}
+
+// String returns the text, quoted
+func (sl StringLiteral) String() string {
+	return `"` + sl.Value + `"`
+}
+
+// TemplateLiteral is a string with interpolated expressions:
+// "Hello ${name}!" has Parts "Hello ", and "!", around Expressions name.
+// There is always one more part than expressions.
+type TemplateLiteral struct {
+	Token       token.Token // the TEMPLATE_HEAD
+	Parts       []string
+	Expressions []Expression
+}
+
+// TokenLiteral allows tl to be an AST node
+func (tl TemplateLiteral) TokenLiteral() string {
+	return tl.Token.Literal
+}
+
+// String returns the text, and expressions, quoted
+func (tl TemplateLiteral) String() string {
+	str := `"`
+	for i, part := range tl.Parts {
+		str += part
+		if i < len(tl.Expressions) {
+			str += "${" + tl.Expressions[i].String() + "}"
+		}
+	}
+	return str + `"`
+}
@@ -0,0 +1,65 @@
+package ast
+
+import (
+	"monkey/token"
+	"strings"
+)
+
+// FunctionLiteral is a function's parameters, and body: fn(x, y) { x + y }.
+// Parameters, and the result can be annotated: fn(x: int, y) -> int { x }
+type FunctionLiteral struct {
+	Token      token.Token
+	Parameters []*Identifier
+	Types      []TypeExpression // each parameter's, nil when it has none
+	Result     TypeExpression   // nil without an annotation
+	Body       *BlockStatement
+}
+
+// TokenLiteral allows fl to be an AST node
+func (fl FunctionLiteral) TokenLiteral() string {
+	return fl.Token.Literal
+}
+
+// Signature returns the function without its body: fn(x: int, y) -> int
+func (fl FunctionLiteral) Signature() string {
+	var params []string
+	for i, p := range fl.Parameters {
+		param := p.String()
+		if fl.Types[i] != nil {
+			param += ": " + fl.Types[i].String()
+		}
+		params = append(params, param)
+	}
+
+	str := "fn(" + strings.Join(params, ", ") + ")"
+	if fl.Result != nil {
+		str += " -> " + fl.Result.String()
+	}
+	return str
+}
+
+// String returns the parameters, and body
+func (fl FunctionLiteral) String() string {
+	return fl.Signature() + " " + fl.Body.String()
+}
+
+// CallExpression is a function called with arguments: add(1, 2)
+type CallExpression struct {
+	Token     token.Token // the (
+	Function  Expression
+	Arguments []Expression
+}
+
+// TokenLiteral allows ce to be an AST node
+func (ce CallExpression) TokenLiteral() string {
+	return ce.Token.Literal
+}
+
+// String returns the function, and arguments
+func (ce CallExpression) String() string {
+	var args []string
+	for _, a := range ce.Arguments {
+		args = append(args, a.String())
+	}
+	return ce.Function.String() + "(" + strings.Join(args, ", ") + ")"
+}
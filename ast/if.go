@@ -0,0 +1,27 @@
+package ast
+
+import "monkey/token"
+
+// IfExpression is the value of one of two blocks, picked by a condition:
+// if (x < y) { x } else { y }
+type IfExpression struct {
+	Token       token.Token
+	Condition   Expression
+	Consequence *BlockStatement
+	Alternative *BlockStatement // nil without an else block
+}
+
+// TokenLiteral allows ie to be an AST node
+func (ie IfExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+// String returns the condition, and blocks
+func (ie IfExpression) String() string {
+	str := "if " + ie.Condition.String() + " " + ie.Consequence.String()
+	if ie.Alternative != nil {
+		str += " else " + ie.Alternative.String()
+	}
+
+	return str
+}
@@ -0,0 +1,69 @@
+package ast
+
+import "monkey/token"
+
+// WhileStatement runs Body while Condition is truthy: while (foo) { }
+type WhileStatement struct {
+	Token     token.Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+// TokenLiteral allows ws to be an AST node
+func (ws WhileStatement) TokenLiteral() string {
+	return ws.Token.Literal
+}
+
+// String returns the condition, and body
+func (ws WhileStatement) String() string {
+	return ws.Token.Literal + " (" + ws.Condition.String() + ") " + ws.Body.String()
+}
+
+// ForStatement runs Body with Variable bound to each element of Iterable:
+// for (foo in bar) { }
+type ForStatement struct {
+	Token    token.Token
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+// TokenLiteral allows fs to be an AST node
+func (fs ForStatement) TokenLiteral() string {
+	return fs.Token.Literal
+}
+
+// String returns the variable, iterable, and body
+func (fs ForStatement) String() string {
+	return fs.Token.Literal + " (" + fs.Variable.String() + " in " + fs.Iterable.String() + ") " + fs.Body.String()
+}
+
+// BreakStatement ends the innermost loop
+type BreakStatement struct {
+	Token token.Token
+}
+
+// TokenLiteral allows bs to be an AST node
+func (bs BreakStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+// String returns token literal
+func (bs BreakStatement) String() string {
+	return bs.Token.Literal
+}
+
+// ContinueStatement skips to the next iteration of the innermost loop
+type ContinueStatement struct {
+	Token token.Token
+}
+
+// TokenLiteral allows cs to be an AST node
+func (cs ContinueStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+
+// String returns token literal
+func (cs ContinueStatement) String() string {
+	return cs.Token.Literal
+}
@@ -0,0 +1,67 @@
+package ast
+
+import (
+	"monkey/token"
+	"strings"
+)
+
+// MacroLiteral is a function from code to code, called while the program is
+// expanded, before it runs: macro(a, b) { quote(unquote(b) - unquote(a)) }
+type MacroLiteral struct {
+	Token      token.Token
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+// TokenLiteral allows ml to be an AST node
+func (ml MacroLiteral) TokenLiteral() string {
+	return ml.Token.Literal
+}
+
+// Signature returns the macro without its body: macro(a, b)
+func (ml MacroLiteral) Signature() string {
+	var params []string
+	for _, p := range ml.Parameters {
+		params = append(params, p.String())
+	}
+	return ml.Token.Literal + "(" + strings.Join(params, ", ") + ")"
+}
+
+// String returns the parameters, and body
+func (ml MacroLiteral) String() string {
+	return ml.Signature() + " " + ml.Body.String()
+}
+
+// QuoteExpression is code as a value, instead of the value of the code:
+// quote(a + b)
+type QuoteExpression struct {
+	Token token.Token
+	Node  Expression
+}
+
+// TokenLiteral allows qe to be an AST node
+func (qe QuoteExpression) TokenLiteral() string {
+	return qe.Token.Literal
+}
+
+// String returns the quoted code, in quote()
+func (qe QuoteExpression) String() string {
+	return qe.Token.Literal + "(" + qe.Node.String() + ")"
+}
+
+// UnquoteExpression is evaluated in quoted code, and replaced by its value:
+// quote(unquote(a) + b)
+type UnquoteExpression struct {
+	Token      token.Token
+	Expression Expression
+}
+
+// TokenLiteral allows ue to be an AST node
+func (ue UnquoteExpression) TokenLiteral() string {
+	return ue.Token.Literal
+}
+
+// String returns the expression, in unquote()
+func (ue UnquoteExpression) String() string {
+	return ue.Token.Literal + "(" + ue.Expression.String() + ")"
+}
@@ -0,0 +1,38 @@
+package ast
+
+import "monkey/token"
+
+// ImportStatement binds a module's exports to Name: import "path/to/mod"
+// Name is the last element of the path, ex: mod
+type ImportStatement struct {
+	Token token.Token
+	Path  *StringLiteral
+	Name  *Identifier
+}
+
+// TokenLiteral allows is to be an AST node
+func (is ImportStatement) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+// String returns token, and path
+func (is ImportStatement) String() string {
+	return is.Token.Literal + " " + is.Path.String() + ";"
+}
+
+// ExportStatement makes a let statement's variable importable:
+// export let foo = 4;
+type ExportStatement struct {
+	Token token.Token
+	Let   *LetStatement
+}
+
+// TokenLiteral allows es to be an AST node
+func (es ExportStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+
+// String returns token, and the exported let statement
+func (es ExportStatement) String() string {
+	return es.Token.Literal + " " + es.Let.String()
+}
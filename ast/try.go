@@ -0,0 +1,52 @@
+package ast
+
+import "monkey/token"
+
+// ThrowStatement contains the thrown value: throw foo;
+type ThrowStatement struct {
+	Token token.Token
+	Value Expression
+}
+
+// TokenLiteral allows ts to be an AST node
+func (ts ThrowStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+
+// String returns token, and thrown value
+func (ts ThrowStatement) String() string {
+	str := ts.Token.Literal
+	if ts.Value != nil {
+		str += " " + ts.Value.String()
+	}
+
+	return str
+}
+
+// TryStatement can be: try { } catch (e) { } finally { }
+// One of Catch, or Finally can be nil.
+type TryStatement struct {
+	Token   token.Token
+	Body    *BlockStatement
+	Param   *Identifier // bound to the thrown value in Catch
+	Catch   *BlockStatement
+	Finally *BlockStatement
+}
+
+// TokenLiteral allows ts to be an AST node
+func (ts TryStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+
+// String returns the try, catch and finally blocks
+func (ts TryStatement) String() string {
+	str := ts.Token.Literal + " " + ts.Body.String()
+	if ts.Catch != nil {
+		str += " catch (" + ts.Param.String() + ") " + ts.Catch.String()
+	}
+	if ts.Finally != nil {
+		str += " finally " + ts.Finally.String()
+	}
+
+	return str
+}
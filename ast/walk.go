@@ -0,0 +1,194 @@
+package ast
+
+import "monkey/token"
+
+// Pos returns where node starts in the input.
+func Pos(node Node) token.Position {
+	switch n := node.(type) {
+	case *Identifier:
+		return n.Token.Pos
+	case *Integer:
+		return n.Token.Pos
+	case *StringLiteral:
+		return n.Token.Pos
+	case *TemplateLiteral:
+		return n.Token.Pos
+	case *PrefixExpression:
+		return n.Token.Pos
+	case *InfixExpression:
+		return Pos(n.Left)
+	case *Boolean:
+		return n.Token.Pos
+	case *IfExpression:
+		return n.Token.Pos
+	case *FunctionLiteral:
+		return n.Token.Pos
+	case *CallExpression:
+		return Pos(n.Function)
+	case *SpawnExpression:
+		return n.Token.Pos
+	case *MacroLiteral:
+		return n.Token.Pos
+	case *QuoteExpression:
+		return n.Token.Pos
+	case *UnquoteExpression:
+		return n.Token.Pos
+	case *SelectStatement:
+		return n.Token.Pos
+	case *SelectCase:
+		return n.Token.Pos
+	case *AssignExpression:
+		return n.Name.Token.Pos
+	case *ExpressionStatement:
+		return n.Token.Pos
+	case *LetStatement:
+		return n.Token.Pos
+	case *ReturnStatement:
+		return n.Token.Pos
+	case *ThrowStatement:
+		return n.Token.Pos
+	case *BlockStatement:
+		return n.Token.Pos
+	case *TryStatement:
+		return n.Token.Pos
+	case *WhileStatement:
+		return n.Token.Pos
+	case *ForStatement:
+		return n.Token.Pos
+	case *BreakStatement:
+		return n.Token.Pos
+	case *ContinueStatement:
+		return n.Token.Pos
+	case *ImportStatement:
+		return n.Token.Pos
+	case *ExportStatement:
+		return n.Token.Pos
+	case *NamedType:
+		return n.Token.Pos
+	case *ArrayType:
+		return n.Token.Pos
+	case *HashType:
+		return n.Token.Pos
+	case *FuncType:
+		return n.Token.Pos
+	case *Program:
+		if len(n.Statements) > 0 {
+			return Pos(n.Statements[0])
+		}
+	}
+	return token.Position{}
+}
+
+// Inspect calls fn with node, and then with each of its children, depth
+// first. Children are skipped when fn returns false.
+func Inspect(node Node, fn func(Node) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		for _, stmt := range n.Statements {
+			Inspect(stmt, fn)
+		}
+	case *BlockStatement:
+		for _, stmt := range n.Statements {
+			Inspect(stmt, fn)
+		}
+	case *LetStatement:
+		Inspect(n.Name, fn)
+		Inspect(n.Type, fn)
+		Inspect(n.Value, fn)
+	case *ImportStatement:
+		Inspect(n.Path, fn)
+		Inspect(n.Name, fn)
+	case *ExportStatement:
+		Inspect(n.Let, fn)
+	case *ReturnStatement:
+		Inspect(n.Value, fn)
+	case *ThrowStatement:
+		Inspect(n.Value, fn)
+	case *ExpressionStatement:
+		Inspect(n.Expression, fn)
+	case *PrefixExpression:
+		Inspect(n.Expression, fn)
+	case *InfixExpression:
+		Inspect(n.Left, fn)
+		Inspect(n.Right, fn)
+	case *IfExpression:
+		Inspect(n.Condition, fn)
+		Inspect(n.Consequence, fn)
+		if n.Alternative != nil {
+			Inspect(n.Alternative, fn)
+		}
+	case *FunctionLiteral:
+		for i, param := range n.Parameters {
+			Inspect(param, fn)
+			Inspect(n.Types[i], fn)
+		}
+		Inspect(n.Result, fn)
+		Inspect(n.Body, fn)
+	case *CallExpression:
+		Inspect(n.Function, fn)
+		for _, arg := range n.Arguments {
+			Inspect(arg, fn)
+		}
+	case *MacroLiteral:
+		for _, param := range n.Parameters {
+			Inspect(param, fn)
+		}
+		Inspect(n.Body, fn)
+	case *QuoteExpression:
+		Inspect(n.Node, fn)
+	case *UnquoteExpression:
+		Inspect(n.Expression, fn)
+	case *SpawnExpression:
+		Inspect(n.Call, fn)
+	case *SelectStatement:
+		for _, c := range n.Cases {
+			Inspect(c, fn)
+		}
+		if n.Default != nil {
+			Inspect(n.Default, fn)
+		}
+	case *SelectCase:
+		if n.Name != nil {
+			Inspect(n.Name, fn)
+		}
+		Inspect(n.Operation, fn)
+		Inspect(n.Body, fn)
+	case *TemplateLiteral:
+		for _, expr := range n.Expressions {
+			Inspect(expr, fn)
+		}
+	case *AssignExpression:
+		Inspect(n.Name, fn)
+		Inspect(n.Value, fn)
+	case *WhileStatement:
+		Inspect(n.Condition, fn)
+		Inspect(n.Body, fn)
+	case *ForStatement:
+		Inspect(n.Variable, fn)
+		Inspect(n.Iterable, fn)
+		Inspect(n.Body, fn)
+	case *TryStatement:
+		Inspect(n.Body, fn)
+		if n.Catch != nil {
+			Inspect(n.Param, fn)
+			Inspect(n.Catch, fn)
+		}
+		if n.Finally != nil {
+			Inspect(n.Finally, fn)
+		}
+	case *ArrayType:
+		Inspect(n.Elem, fn)
+	case *HashType:
+		Inspect(n.Key, fn)
+		Inspect(n.Value, fn)
+	case *FuncType:
+		for _, param := range n.Params {
+			Inspect(param, fn)
+		}
+		Inspect(n.Result, fn)
+	}
+}
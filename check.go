@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/parser"
+	"monkey/types"
+	"os"
+)
+
+// check type checks the files in args, printing type errors to stdout. It
+// returns the process' exit code: 1 if there were errors, 2 if check
+// couldn't run.
+func check(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	showTypes := fs.Bool("types", false, "print the inferred type of every let statement")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: monkey check [flags] file...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	failed := false
+	for _, file := range fs.Args() {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed reading %s: %s\n", file, err)
+			return 2
+		}
+
+		prog, err := parser.New(lexer.New(string(b))).Parse()
+		if err != nil {
+			for _, e := range err.(parser.ErrorList) {
+				fmt.Printf("%s:%s\n", file, e)
+			}
+			failed = true
+			continue
+		}
+
+		typs, diags := types.Check(prog)
+		for _, d := range diags {
+			fmt.Printf("%s:%s\n", file, d)
+		}
+		failed = failed || len(diags) > 0
+
+		if *showTypes {
+			ast.Inspect(prog, func(n ast.Node) bool {
+				if let, ok := n.(*ast.LetStatement); ok {
+					fmt.Printf("%s:%s: %s %s\n", file, let.Name.Token.Pos, let.Name.Value, typs[let.Name])
+				}
+				return true
+			})
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
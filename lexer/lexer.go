@@ -10,13 +10,17 @@ type Lexer struct {
 	position     int  // current char
 	readPosition int  // after current char
 	ch           byte // current char
+	line         int  // line of current char
+	column       int  // column of current char
+	comments     []token.Token
+	templates    []int // braces open in each interpolation being lexed, innermost last
 }
 
 const nullChar = 0 // ASCI code for null
 
 // New creates a lexer
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar()
 	return l
 }
@@ -26,6 +30,11 @@ func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
 
 	l.skipWhiteSpace()
+	for l.ch == '/' && l.peekChar() == '/' {
+		l.readComment()
+		l.skipWhiteSpace()
+	}
+	pos := token.Position{Line: l.line, Column: l.column}
 
 	switch l.ch {
 	case []byte(token.SEMICOLON)[0]:
@@ -34,6 +43,9 @@ func (l *Lexer) NextToken() token.Token {
 	case []byte(token.COMMA)[0]:
 		tok = token.Token{Type: token.COMMA, Literal: string(l.ch)}
 
+	case []byte(token.COLON)[0]:
+		tok = token.Token{Type: token.COLON, Literal: string(l.ch)}
+
 	case []byte(token.LPAREN)[0]:
 		tok = token.Token{Type: token.LPAREN, Literal: string(l.ch)}
 
@@ -42,10 +54,29 @@ func (l *Lexer) NextToken() token.Token {
 
 	case []byte(token.LBRACE)[0]:
 		tok = token.Token{Type: token.LBRACE, Literal: string(l.ch)}
+		if n := len(l.templates); n > 0 {
+			l.templates[n-1]++
+		}
 
 	case []byte(token.RBRACE)[0]:
+		n := len(l.templates)
+		if n > 0 && l.templates[n-1] == 0 {
+			// closes an interpolation, the string goes on after it
+			l.templates = l.templates[:n-1]
+			tok = l.readTemplate(token.TEMPLATE_MIDDLE, token.TEMPLATE_TAIL)
+			break
+		}
+		if n > 0 {
+			l.templates[n-1]--
+		}
 		tok = token.Token{Type: token.RBRACE, Literal: string(l.ch)}
 
+	case []byte(token.LBRACKET)[0]:
+		tok = token.Token{Type: token.LBRACKET, Literal: string(l.ch)}
+
+	case []byte(token.RBRACKET)[0]:
+		tok = token.Token{Type: token.RBRACKET, Literal: string(l.ch)}
+
 	case []byte(token.ASSIGN)[0]:
 		if l.peekChar() == []byte(token.ASSIGN)[0] {
 			ch := l.ch
@@ -57,10 +88,15 @@ func (l *Lexer) NextToken() token.Token {
 		}
 
 	case []byte(token.PLUS)[0]:
-		tok = token.Token{Type: token.PLUS, Literal: string(l.ch)}
+		tok = l.readCompound(token.PLUS, token.PLUS_ASSIGN)
 
 	case []byte(token.MINUS)[0]:
-		tok = token.Token{Type: token.MINUS, Literal: string(l.ch)}
+		if l.peekChar() == []byte(token.GT)[0] {
+			l.readChar()
+			tok = token.Token{Type: token.ARROW, Literal: token.ARROW}
+		} else {
+			tok = l.readCompound(token.MINUS, token.MINUS_ASSIGN)
+		}
 
 	case []byte(token.BANG)[0]:
 		if l.peekChar() == []byte(token.EQ)[0] {
@@ -73,10 +109,10 @@ func (l *Lexer) NextToken() token.Token {
 		}
 
 	case []byte(token.ASTERISK)[0]:
-		tok = token.Token{Type: token.ASTERISK, Literal: string(l.ch)}
+		tok = l.readCompound(token.ASTERISK, token.ASTERISK_ASSIGN)
 
 	case []byte(token.SLASH)[0]:
-		tok = token.Token{Type: token.SLASH, Literal: string(l.ch)}
+		tok = l.readCompound(token.SLASH, token.SLASH_ASSIGN)
 
 	case []byte(token.LT)[0]:
 		tok = token.Token{Type: token.LT, Literal: string(l.ch)}
@@ -84,22 +120,61 @@ func (l *Lexer) NextToken() token.Token {
 	case []byte(token.GT)[0]:
 		tok = token.Token{Type: token.GT, Literal: string(l.ch)}
 
+	case []byte(token.PIPE)[0]:
+		if l.peekChar() == []byte(token.PIPE)[1] {
+			l.readChar()
+			tok = token.Token{Type: token.PIPE, Literal: token.PIPE}
+		} else {
+			tok = token.Token{Type: token.ILLEGAL, Literal: string(l.ch)}
+		}
+
+	case '"':
+		tok = l.readTemplate(token.TEMPLATE_HEAD, token.STRING)
+
 	case nullChar: // NULL
 		tok = token.Token{Type: token.EOF, Literal: ""}
 	default:
 		if isValidIdentChar(l.ch) {
 			ident := l.readIdentifier()
-			return token.Token{Type: token.IdentType(ident), Literal: ident}
+			return token.Token{Type: token.IdentType(ident), Literal: ident, Pos: pos}
 		} else if isDigit(l.ch) {
-			return token.Token{Type: token.INT, Literal: l.readInt()}
+			return token.Token{Type: token.INT, Literal: l.readInt(), Pos: pos}
 		}
 		tok = token.Token{Type: token.ILLEGAL, Literal: string(l.ch)}
 	}
 
+	tok.Pos = pos
 	l.readChar()
 	return tok
 }
 
+// Comments returns the comments skipped so far.
+func (l *Lexer) Comments() []token.Token {
+	return l.comments
+}
+
+// reads char until end of line, saving the comment
+func (l *Lexer) readComment() {
+	pos := token.Position{Line: l.line, Column: l.column}
+	start := l.position
+	for l.ch != '\n' && l.ch != nullChar {
+		l.readChar()
+	}
+	lit := l.input[start:l.position]
+	l.comments = append(l.comments, token.Token{Type: token.COMMENT, Literal: lit, Pos: pos})
+}
+
+// readCompound returns a token of type compound if the next char is =, ex: +=
+// and a token of type typ otherwise.
+func (l *Lexer) readCompound(typ, compound token.Type) token.Token {
+	if l.peekChar() == []byte(token.ASSIGN)[0] {
+		ch := l.ch
+		l.readChar()
+		return token.Token{Type: compound, Literal: string(ch) + string(l.ch)}
+	}
+	return token.Token{Type: typ, Literal: string(l.ch)}
+}
+
 func (l *Lexer) peekChar() byte {
 	if l.readPosition >= len(l.input) {
 		return 0
@@ -136,6 +211,40 @@ func inByteArray(ch byte, chs []byte) bool {
 	return false
 }
 
+// readTemplate reads string text from the opening quote, or from the } that
+// closes an interpolation. It returns a token of type open if the text ends
+// at an interpolation's ${, and of type closed if it ends at the closing quote.
+func (l *Lexer) readTemplate(open, closed token.Type) token.Token {
+	first := l.ch
+	lit, end := l.readString()
+	switch end {
+	case '"':
+		return token.Token{Type: closed, Literal: lit}
+	case '$':
+		l.templates = append(l.templates, 0)
+		return token.Token{Type: open, Literal: lit}
+	}
+	return token.Token{Type: token.ILLEGAL, Literal: string(first) + lit}
+}
+
+// reads chars until the closing quote, or the ${ starting an interpolation,
+// returning the text before it, and the char it ended at: ", $, or the
+// newline, or null char if the line, or input ends first.
+func (l *Lexer) readString() (lit string, end byte) {
+	start := l.position + 1
+	for {
+		l.readChar()
+		switch {
+		case l.ch == '"', l.ch == '\n', l.ch == nullChar:
+			return l.input[start:l.position], l.ch
+		case l.ch == '$' && l.peekChar() == '{':
+			lit = l.input[start:l.position]
+			l.readChar()
+			return lit, '$'
+		}
+	}
+}
+
 // reads char until end of identifier
 func (l *Lexer) readIdentifier() string {
 	start := l.position
@@ -151,6 +260,12 @@ func isValidIdentChar(ch byte) bool {
 }
 
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+	l.column++
+
 	if l.readPosition >= len(l.input) {
 		l.ch = nullChar
 	} else {
@@ -159,3 +159,172 @@ func BenchmarkNextToken(b *testing.B) {
 	b.StopTimer()
 
 }
+
+func TestNextTokenPosition(t *testing.T) {
+	input := "let five = 5;\n  -five"
+	want := []token.Position{
+		{Line: 1, Column: 1},
+		{Line: 1, Column: 5},
+		{Line: 1, Column: 10},
+		{Line: 1, Column: 12},
+		{Line: 1, Column: 13},
+		{Line: 2, Column: 3},
+		{Line: 2, Column: 4},
+		{Line: 2, Column: 8},
+	}
+
+	lex := lexer.New(input)
+	for i, want := range want {
+		tok := lex.NextToken()
+		if tok.Pos != want {
+			t.Fatalf("wrong token %v %q: have position %s want %s", i, tok.Literal, tok.Pos, want)
+		}
+	}
+}
+
+func TestNextTokenComment(t *testing.T) {
+	input := "// first\nlet five = 5; // second\n//third"
+	wantToks := []token.Type{token.LET, token.IDENT, token.ASSIGN, token.INT, token.SEMICOLON, token.EOF}
+	wantComments := []token.Token{
+		{Type: token.COMMENT, Literal: "// first", Pos: token.Position{Line: 1, Column: 1}},
+		{Type: token.COMMENT, Literal: "// second", Pos: token.Position{Line: 2, Column: 15}},
+		{Type: token.COMMENT, Literal: "//third", Pos: token.Position{Line: 3, Column: 1}},
+	}
+
+	lex := lexer.New(input)
+	for i, want := range wantToks {
+		tok := lex.NextToken()
+		if tok.Type != want {
+			t.Fatalf("wrong token %v: have type %s want %s", i, tok.Type, want)
+		}
+	}
+
+	comments := lex.Comments()
+	if len(comments) != len(wantComments) {
+		t.Fatalf("have %v comments, want %v", len(comments), len(wantComments))
+	}
+	for i, want := range wantComments {
+		if comments[i] != want {
+			t.Fatalf("wrong comment %v: have %+v want %+v", i, comments[i], want)
+		}
+	}
+}
+
+func TestNextTokenKeywords(t *testing.T) {
+	input := `try catch finally throw while for in break continue`
+	want := []token.Type{
+		token.TRY, token.CATCH, token.FINALLY, token.THROW,
+		token.WHILE, token.FOR, token.IN, token.BREAK, token.CONTINUE,
+		token.EOF,
+	}
+
+	lex := lexer.New(input)
+	for i, want := range want {
+		tok := lex.NextToken()
+		if tok.Type != want {
+			t.Fatalf("wrong token %v: have type %s want %s", i, tok.Type, want)
+		}
+	}
+}
+
+func TestNextTokenCompoundAssign(t *testing.T) {
+	input := `x += 1; x -= 1; x *= 2; x /= 2; x = -1 / 2;`
+	want := []token.Token{
+		{Type: token.IDENT, Literal: "x"}, {Type: token.PLUS_ASSIGN, Literal: "+="}, {Type: token.INT, Literal: "1"}, {Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.IDENT, Literal: "x"}, {Type: token.MINUS_ASSIGN, Literal: "-="}, {Type: token.INT, Literal: "1"}, {Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.IDENT, Literal: "x"}, {Type: token.ASTERISK_ASSIGN, Literal: "*="}, {Type: token.INT, Literal: "2"}, {Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.IDENT, Literal: "x"}, {Type: token.SLASH_ASSIGN, Literal: "/="}, {Type: token.INT, Literal: "2"}, {Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.IDENT, Literal: "x"}, {Type: token.ASSIGN, Literal: "="}, {Type: token.MINUS, Literal: "-"}, {Type: token.INT, Literal: "1"},
+		{Type: token.SLASH, Literal: "/"}, {Type: token.INT, Literal: "2"}, {Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.EOF, Literal: ""},
+	}
+
+	lex := lexer.New(input)
+	for i, want := range want {
+		tok := lex.NextToken()
+		if tok.Type != want.Type || tok.Literal != want.Literal {
+			t.Fatalf("wrong token %v: have %s %q want %s %q", i, tok.Type, tok.Literal, want.Type, want.Literal)
+		}
+	}
+}
+
+func TestNextTokenString(t *testing.T) {
+	input := "\"foo bar\" \"\" \"open\n\"closed"
+	want := []token.Token{
+		{Type: token.STRING, Literal: "foo bar"},
+		{Type: token.STRING, Literal: ""},
+		{Type: token.ILLEGAL, Literal: "\"open"},
+		{Type: token.ILLEGAL, Literal: "\"closed"},
+		{Type: token.EOF, Literal: ""},
+	}
+
+	lex := lexer.New(input)
+	for i, want := range want {
+		tok := lex.NextToken()
+		if tok.Type != want.Type || tok.Literal != want.Literal {
+			t.Fatalf("wrong token %v: have %s %q want %s %q", i, tok.Type, tok.Literal, want.Type, want.Literal)
+		}
+	}
+}
+
+func TestNextTokenTypeAnnotation(t *testing.T) {
+	input := `let f: fn([int], {string: int}) -> bool = x - 1;`
+	want := []token.Token{
+		{Type: token.LET, Literal: "let"}, {Type: token.IDENT, Literal: "f"}, {Type: token.COLON, Literal: ":"},
+		{Type: token.FUNCTION, Literal: "fn"}, {Type: token.LPAREN, Literal: "("},
+		{Type: token.LBRACKET, Literal: "["}, {Type: token.IDENT, Literal: "int"}, {Type: token.RBRACKET, Literal: "]"}, {Type: token.COMMA, Literal: ","},
+		{Type: token.LBRACE, Literal: "{"}, {Type: token.IDENT, Literal: "string"}, {Type: token.COLON, Literal: ":"}, {Type: token.IDENT, Literal: "int"}, {Type: token.RBRACE, Literal: "}"},
+		{Type: token.RPAREN, Literal: ")"}, {Type: token.ARROW, Literal: "->"}, {Type: token.IDENT, Literal: "bool"},
+		{Type: token.ASSIGN, Literal: "="}, {Type: token.IDENT, Literal: "x"}, {Type: token.MINUS, Literal: "-"}, {Type: token.INT, Literal: "1"}, {Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.EOF, Literal: ""},
+	}
+
+	lex := lexer.New(input)
+	for i, want := range want {
+		tok := lex.NextToken()
+		if tok.Type != want.Type || tok.Literal != want.Literal {
+			t.Fatalf("wrong token %v: have %s %q want %s %q", i, tok.Type, tok.Literal, want.Type, want.Literal)
+		}
+	}
+}
+
+func TestNextTokenTemplate(t *testing.T) {
+	input := `"Hello ${name}, you have ${count + 1} items" "${ {} } ${"in ${x}"}" "a ${x`
+	want := []token.Token{
+		{Type: token.TEMPLATE_HEAD, Literal: "Hello "}, {Type: token.IDENT, Literal: "name"},
+		{Type: token.TEMPLATE_MIDDLE, Literal: ", you have "}, {Type: token.IDENT, Literal: "count"}, {Type: token.PLUS, Literal: "+"}, {Type: token.INT, Literal: "1"},
+		{Type: token.TEMPLATE_TAIL, Literal: " items"},
+		// braces, and strings inside interpolations
+		{Type: token.TEMPLATE_HEAD, Literal: ""}, {Type: token.LBRACE, Literal: "{"}, {Type: token.RBRACE, Literal: "}"},
+		{Type: token.TEMPLATE_MIDDLE, Literal: " "}, {Type: token.TEMPLATE_HEAD, Literal: "in "}, {Type: token.IDENT, Literal: "x"},
+		{Type: token.TEMPLATE_TAIL, Literal: ""}, {Type: token.TEMPLATE_TAIL, Literal: ""},
+		{Type: token.TEMPLATE_HEAD, Literal: "a "}, {Type: token.IDENT, Literal: "x"},
+		{Type: token.EOF, Literal: ""},
+	}
+
+	lex := lexer.New(input)
+	for i, want := range want {
+		tok := lex.NextToken()
+		if tok.Type != want.Type || tok.Literal != want.Literal {
+			t.Fatalf("wrong token %v: have %s %q want %s %q", i, tok.Type, tok.Literal, want.Type, want.Literal)
+		}
+	}
+}
+
+func TestNextTokenPipe(t *testing.T) {
+	input := `xs |> filter(_, f) | x`
+	want := []token.Token{
+		{Type: token.IDENT, Literal: "xs"}, {Type: token.PIPE, Literal: "|>"}, {Type: token.IDENT, Literal: "filter"},
+		{Type: token.LPAREN, Literal: "("}, {Type: token.IDENT, Literal: "_"}, {Type: token.COMMA, Literal: ","}, {Type: token.IDENT, Literal: "f"}, {Type: token.RPAREN, Literal: ")"},
+		{Type: token.ILLEGAL, Literal: "|"}, {Type: token.IDENT, Literal: "x"},
+		{Type: token.EOF, Literal: ""},
+	}
+
+	lex := lexer.New(input)
+	for i, want := range want {
+		tok := lex.NextToken()
+		if tok.Type != want.Type || tok.Literal != want.Literal {
+			t.Fatalf("wrong token %v: have %s %q want %s %q", i, tok.Type, tok.Literal, want.Type, want.Literal)
+		}
+	}
+}
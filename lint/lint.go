@@ -0,0 +1,214 @@
+package lint
+
+import (
+	"fmt"
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/parser"
+	"monkey/token"
+	"sort"
+	"strings"
+)
+
+// Severity is how serious a Finding is.
+type Severity string
+
+const (
+	// Error is code that is wrong
+	Error Severity = "error"
+	// Warning is code that is likely wrong
+	Warning Severity = "warning"
+	// Info is code that is fine, but could be clearer
+	Info Severity = "info"
+)
+
+// ParseRule is the rule name of findings made from parse errors
+const ParseRule = "parse"
+
+// ignoreDirective in a comment suppresses findings on its line, and on the
+// line after it if the comment is alone on its line. It can be followed by
+// comma separated rule names, ex: vet:ignore double-negation,unused
+const ignoreDirective = "vet:ignore"
+
+// Fix is a suggested edit, replacing the input from Pos up to End with NewText.
+type Fix struct {
+	Message string         `json:"message"`
+	Pos     token.Position `json:"pos"`
+	End     token.Position `json:"end"`
+	NewText string         `json:"newText"`
+}
+
+// Finding is a problem found by a rule.
+type Finding struct {
+	Rule     string         `json:"rule"`
+	Severity Severity       `json:"severity"`
+	Pos      token.Position `json:"pos"`
+	Msg      string         `json:"message"`
+	Fix      *Fix           `json:"fix,omitempty"`
+}
+
+// String returns the finding's position, rule and message
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Pos, f.Severity, f.Msg, f.Rule)
+}
+
+// Rule is an analyzer that reports findings in a program.
+type Rule struct {
+	Name     string
+	Doc      string
+	Severity Severity
+	Default  bool // is the rule run when none are chosen
+	Run      func(pass *Pass)
+}
+
+// Pass is a rule running over a program.
+type Pass struct {
+	Prog     *ast.Program
+	rule     *Rule
+	findings []Finding
+}
+
+// Report adds a finding at pos. fix can be nil.
+func (p *Pass) Report(pos token.Position, fix *Fix, format string, args ...interface{}) {
+	p.findings = append(p.findings, Finding{
+		Rule:     p.rule.Name,
+		Severity: p.rule.Severity,
+		Pos:      pos,
+		Msg:      fmt.Sprintf(format, args...),
+		Fix:      fix,
+	})
+}
+
+var rules = map[string]*Rule{}
+
+// Register makes a rule available to Lint. It panics if the rule's name is
+// taken.
+func Register(r *Rule) {
+	if _, ok := rules[r.Name]; ok {
+		panic("lint: rule registered twice: " + r.Name)
+	}
+	rules[r.Name] = r
+}
+
+// Lookup returns the registered rule called name, or nil.
+func Lookup(name string) *Rule {
+	return rules[name]
+}
+
+// Rules returns every registered rule, sorted by name.
+func Rules() []*Rule {
+	var rs []*Rule
+	for _, r := range rules {
+		rs = append(rs, r)
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i].Name < rs[j].Name })
+	return rs
+}
+
+// DefaultRules returns the registered rules run when none are chosen.
+func DefaultRules() []*Rule {
+	var rs []*Rule
+	for _, r := range Rules() {
+		if r.Default {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// Lint parses input, and runs rules over it. Findings suppressed by a
+// vet:ignore comment are dropped. If input doesn't parse, the parse errors
+// are returned as findings, and no rules are run.
+func Lint(input string, rules []*Rule) []Finding {
+	l := lexer.New(input)
+	prog, err := parser.New(l).Parse()
+	if err != nil {
+		var findings []Finding
+		for _, e := range err.(parser.ErrorList) {
+			findings = append(findings, Finding{Rule: ParseRule, Severity: Error, Pos: e.Pos, Msg: e.Err.Error()})
+		}
+		return findings
+	}
+
+	var findings []Finding
+	for _, r := range rules {
+		pass := &Pass{Prog: prog, rule: r}
+		r.Run(pass)
+		findings = append(findings, pass.findings...)
+	}
+
+	ignored := ignoredRules(input, l.Comments())
+	var kept []Finding
+	for _, f := range findings {
+		if !ignored.has(f.Pos.Line, f.Rule) {
+			kept = append(kept, f)
+		}
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		a, b := kept[i].Pos, kept[j].Pos
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return kept
+}
+
+// ignores is the rules ignored on each line. An empty list ignores every rule.
+type ignores map[int][]string
+
+func (ig ignores) has(line int, rule string) bool {
+	names, ok := ig[line]
+	if !ok {
+		return false
+	}
+	if len(names) == 0 {
+		return true
+	}
+	for _, name := range names {
+		if name == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func ignoredRules(input string, comments []token.Token) ignores {
+	lines := strings.Split(input, "\n")
+	ig := ignores{}
+	for _, c := range comments {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Literal, "//"))
+		if !strings.HasPrefix(text, ignoreDirective) {
+			continue
+		}
+		// vet:ignored isn't the directive
+		rest := strings.TrimPrefix(text, ignoreDirective)
+		if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue
+		}
+
+		var names []string
+		for _, name := range strings.Split(rest, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		ig.add(c.Pos.Line, names)
+
+		// a comment after code only ignores findings in that code
+		before := lines[c.Pos.Line-1][:c.Pos.Column-1]
+		if strings.TrimSpace(before) == "" {
+			ig.add(c.Pos.Line+1, names)
+		}
+	}
+	return ig
+}
+
+func (ig ignores) add(line int, names []string) {
+	prev, ok := ig[line]
+	if ok && len(prev) == 0 {
+		return
+	}
+	if ok && len(names) > 0 {
+		names = append(append([]string{}, prev...), names...)
+	}
+	ig[line] = names
+}
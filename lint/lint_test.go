@@ -0,0 +1,123 @@
+package lint_test
+
+import (
+	"monkey/lint"
+	"monkey/token"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	input := `let foo = --5;
+let bar = !!!foo;
+return -bar;
+baz; // vet:ignore
+// vet:ignore unused, undefined
+let qux = quux;
+let one = 1; let one = -one;
+let f = fn() { return 1; };
+f();
+`
+	want := []string{
+		"1:11: warning: double negation -- (double-negation)",
+		"2:11: warning: double negation !! (double-negation)",
+		"3:1: warning: return outside of a function (top-level-return)",
+		"7:18: warning: one declared but not used (unused)",
+	}
+
+	findings := lint.Lint(input, lint.DefaultRules())
+	if len(findings) != len(want) {
+		t.Fatalf("have %v findings %v, want %v", len(findings), findings, len(want))
+	}
+	for i, f := range findings {
+		if f.String() != want[i] {
+			t.Fatalf("have finding %s, want %s", f, want[i])
+		}
+	}
+
+	fix := findings[0].Fix
+	if fix == nil {
+		t.Fatalf("have no fix for %s", findings[0])
+	}
+	if wantPos, wantEnd := (token.Position{Line: 1, Column: 11}), (token.Position{Line: 1, Column: 13}); fix.Pos != wantPos || fix.End != wantEnd {
+		t.Fatalf("have fix from %s to %s, want from %s to %s", fix.Pos, fix.End, wantPos, wantEnd)
+	}
+	if findings[1].Fix != nil {
+		t.Fatalf("have fix %+v for %s, want none", findings[1].Fix, findings[1])
+	}
+}
+
+func TestLintParseErrors(t *testing.T) {
+	input := `let = 5;`
+	want := "1:1: error: have next token type =, want IDENT (parse)"
+
+	findings := lint.Lint(input, lint.DefaultRules())
+	if len(findings) != 1 {
+		t.Fatalf("have %v findings %v, want %v", len(findings), findings, 1)
+	}
+	if findings[0].String() != want {
+		t.Fatalf("have finding %s, want %s", findings[0], want)
+	}
+}
+
+func TestRules(t *testing.T) {
+	if lint.Lookup("shadow") == nil {
+		t.Fatalf("have no rule shadow")
+	}
+	for _, r := range lint.DefaultRules() {
+		if r.Name == "shadow" {
+			t.Fatalf("have shadow in default rules")
+		}
+	}
+
+	findings := lint.Lint(`let one = 1; let one = one; one;`, []*lint.Rule{lint.Lookup("shadow")})
+	want := "1:18: info: one shadows declaration at 1:5 (shadow)"
+	if len(findings) != 1 || findings[0].String() != want {
+		t.Fatalf("have findings %v, want %s", findings, want)
+	}
+}
+
+func TestLintIgnoreTrailing(t *testing.T) {
+	input := `foo; // vet:ignore
+bar;
+baz; // vet:ignored
+`
+	want := []string{
+		"2:1: error: undefined: bar (undefined)",
+		"3:1: error: undefined: baz (undefined)",
+	}
+
+	findings := lint.Lint(input, lint.DefaultRules())
+	if len(findings) != len(want) {
+		t.Fatalf("have %v findings %v, want %v", len(findings), findings, len(want))
+	}
+	for i, f := range findings {
+		if f.String() != want[i] {
+			t.Fatalf("have finding %s, want %s", f, want[i])
+		}
+	}
+}
+
+func TestLintBlocksAndComparisons(t *testing.T) {
+	input := `let x = 1;
+while (x == x) { }
+try { x; } catch (e) { } finally { x != x; }
+if (x < 2) { x; } else { }
+`
+	want := []string{
+		"2:8: warning: (x == x) is always true (self-comparison)",
+		"2:16: warning: empty block (empty-block)",
+		"3:22: warning: empty block (empty-block)",
+		"3:36: warning: (x != x) is always false (self-comparison)",
+		"4:24: warning: empty block (empty-block)",
+	}
+
+	findings := lint.Lint(input, lint.DefaultRules())
+	if len(findings) != len(want) {
+		t.Fatalf("have %v findings %v, want %v", len(findings), findings, len(want))
+	}
+	for i, f := range findings {
+		if f.String() != want[i] {
+			t.Fatalf("have finding %s, want %s", f, want[i])
+		}
+	}
+}
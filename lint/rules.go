@@ -0,0 +1,157 @@
+package lint
+
+import (
+	"monkey/ast"
+	"monkey/resolver"
+	"monkey/token"
+)
+
+func init() {
+	Register(&Rule{
+		Name:     "double-negation",
+		Doc:      "reports !!x and --x, which can be written without the operators",
+		Severity: Warning,
+		Default:  true,
+		Run:      doubleNegation,
+	})
+	Register(&Rule{
+		Name:     "top-level-return",
+		Doc:      "reports return statements outside of functions",
+		Severity: Warning,
+		Default:  true,
+		Run:      topLevelReturn,
+	})
+	Register(&Rule{
+		Name:     "empty-block",
+		Doc:      "reports blocks without statements, ex: catch (e) { }",
+		Severity: Warning,
+		Default:  true,
+		Run:      emptyBlock,
+	})
+	Register(&Rule{
+		Name:     "self-comparison",
+		Doc:      "reports variables compared with themselves, ex: x == x",
+		Severity: Warning,
+		Default:  true,
+		Run:      selfComparison,
+	})
+	Register(&Rule{
+		Name:     "undefined",
+		Doc:      "reports variables used, or assigned without being bound",
+		Severity: Error,
+		Default:  true,
+		Run:      resolverRule(resolver.Undefined),
+	})
+	Register(&Rule{
+		Name:     "unused",
+		Doc:      "reports variables bound, but never used",
+		Severity: Warning,
+		Default:  true,
+		Run:      resolverRule(resolver.Unused),
+	})
+	Register(&Rule{
+		Name:     "const-assign",
+		Doc:      "reports const variables being reassigned",
+		Severity: Error,
+		Default:  true,
+		Run:      resolverRule(resolver.ConstAssigned),
+	})
+	Register(&Rule{
+		Name:     "shadow",
+		Doc:      "reports variables bound again, hiding their previous binding",
+		Severity: Info,
+		Run:      resolverRule(resolver.Shadowed),
+	})
+}
+
+func doubleNegation(pass *Pass) {
+	seen := map[*ast.PrefixExpression]bool{}
+	ast.Inspect(pass.Prog, func(node ast.Node) bool {
+		outer, ok := node.(*ast.PrefixExpression)
+		if !ok || seen[outer] {
+			return true
+		}
+		inner, ok := outer.Expression.(*ast.PrefixExpression)
+		if !ok || inner.Operator != outer.Operator {
+			return true
+		}
+		// !!!x is reported once, not as !!(!x) and !(!!x)
+		seen[inner] = true
+
+		fix := &Fix{
+			Message: "remove " + outer.Operator + inner.Operator,
+			Pos:     outer.Token.Pos,
+			End:     ast.Pos(inner.Expression),
+		}
+		if outer.Operator == token.BANG {
+			fix = nil // !!x is a boolean, x might not be
+		}
+		pass.Report(outer.Token.Pos, fix, "double negation %s%s", outer.Operator, inner.Operator)
+		return true
+	})
+}
+
+// topLevelReturn reports return statements that aren't in a function, or
+// macro literal.
+func topLevelReturn(pass *Pass) {
+	ast.Inspect(pass.Prog, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.FunctionLiteral, *ast.MacroLiteral:
+			return false
+		}
+		ret, ok := node.(*ast.ReturnStatement)
+		if !ok {
+			return true
+		}
+		fix := &Fix{Message: "remove return", Pos: ret.Token.Pos, End: ast.Pos(ret.Value)}
+		pass.Report(ret.Token.Pos, fix, "return outside of a function")
+		return true
+	})
+}
+
+func emptyBlock(pass *Pass) {
+	ast.Inspect(pass.Prog, func(node ast.Node) bool {
+		block, ok := node.(*ast.BlockStatement)
+		if ok && len(block.Statements) == 0 {
+			pass.Report(block.Token.Pos, nil, "empty block")
+		}
+		return true
+	})
+}
+
+// selfComparison reports comparisons of a variable with itself, which are
+// always true, or always false.
+func selfComparison(pass *Pass) {
+	ast.Inspect(pass.Prog, func(node ast.Node) bool {
+		infix, ok := node.(*ast.InfixExpression)
+		if !ok {
+			return true
+		}
+		left, ok := infix.Left.(*ast.Identifier)
+		if !ok {
+			return true
+		}
+		right, ok := infix.Right.(*ast.Identifier)
+		if !ok || left.Value != right.Value {
+			return true
+		}
+
+		switch infix.Operator {
+		case token.EQ:
+			pass.Report(ast.Pos(infix), nil, "%s is always true", infix)
+		case token.NOT_EQ, token.LT, token.GT:
+			pass.Report(ast.Pos(infix), nil, "%s is always false", infix)
+		}
+		return true
+	})
+}
+
+func resolverRule(kind resolver.Kind) func(*Pass) {
+	return func(pass *Pass) {
+		for _, d := range resolver.Resolve(pass.Prog) {
+			if d.Kind == kind {
+				pass.Report(d.Pos, nil, "%s", d.Msg)
+			}
+		}
+	}
+}
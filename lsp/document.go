@@ -0,0 +1,269 @@
+package lsp
+
+import (
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/lint"
+	"monkey/parser"
+	"monkey/resolver"
+	"monkey/token"
+	"sort"
+	"strings"
+)
+
+// document is an open file, and what was learned from parsing it.
+type document struct {
+	uri    string
+	text   string
+	lines  []string
+	tokens []token.Token // including comments, ordered by position
+	idents []*ast.Identifier
+	decls  resolver.Declarations
+	lets   map[*ast.Identifier]*ast.LetStatement // by the name they bind
+	params map[*ast.Identifier]string            // imports, catch, for, select case, function and macro parameters, to how they're bound
+
+	typeBraces map[token.Position]bool // where hash types' { are
+}
+
+func newDocument(uri, text string) *document {
+	d := &document{uri: uri, text: text, lines: strings.Split(text, "\n"), lets: map[*ast.Identifier]*ast.LetStatement{}, params: map[*ast.Identifier]string{}, typeBraces: map[token.Position]bool{}}
+
+	l := lexer.New(text)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		d.tokens = append(d.tokens, tok)
+	}
+	d.tokens = append(d.tokens, l.Comments()...)
+	sort.SliceStable(d.tokens, func(i, j int) bool { return before(d.tokens[i].Pos, d.tokens[j].Pos) })
+
+	// a program with errors is still worth navigating, so they are ignored
+	// here, and reported by diagnostics
+	prog, _ := parser.New(lexer.New(text)).Parse()
+	_, d.decls = resolver.ResolveDeclarations(prog)
+
+	ast.Inspect(prog, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Identifier:
+			// the names the parser makes for partial application aren't in
+			// the text
+			if node.Value == node.Token.Literal {
+				d.idents = append(d.idents, node)
+			}
+		case *ast.LetStatement:
+			d.lets[node.Name] = node
+		case *ast.TryStatement:
+			if node.Param != nil {
+				d.params[node.Param] = "catch (" + node.Param.Value + ")"
+			}
+		case *ast.ImportStatement:
+			d.params[node.Name] = node.String()
+		case *ast.ForStatement:
+			d.params[node.Variable] = "for (" + node.Variable.Value + " in " + node.Iterable.String() + ")"
+		case *ast.SelectCase:
+			if node.Name != nil {
+				d.params[node.Name] = "case " + node.Name.Value + " = " + node.Operation.String()
+			}
+		case *ast.FunctionLiteral:
+			for _, param := range node.Parameters {
+				d.params[param] = node.Signature()
+			}
+		case *ast.MacroLiteral:
+			for _, param := range node.Parameters {
+				d.params[param] = node.Signature()
+			}
+		case *ast.HashType:
+			d.typeBraces[node.Token.Pos] = true
+		}
+		return true
+	})
+	// a pipe's left operand comes before its call's function
+	sort.SliceStable(d.idents, func(i, j int) bool { return before(d.idents[i].Token.Pos, d.idents[j].Token.Pos) })
+
+	return d
+}
+
+// position returns pos as the protocol counts it, in UTF-16 code units from
+// the start of its line.
+func (d *document) position(pos token.Position) position {
+	line := d.line(pos.Line)
+	col := pos.Column - 1
+	if col > len(line) {
+		col = len(line)
+	}
+	return position{Line: pos.Line - 1, Character: utf16Len(line[:col])}
+}
+
+// tokenPosition returns p as the lexer counts it, in bytes.
+func (d *document) tokenPosition(p position) token.Position {
+	line := d.line(p.Line + 1)
+	units := 0
+	for i, r := range line {
+		if units >= p.Character {
+			return token.Position{Line: p.Line + 1, Column: i + 1}
+		}
+		units += utf16Len(string(r))
+	}
+	return token.Position{Line: p.Line + 1, Column: len(line) + 1}
+}
+
+// line returns the text of line n, counting from 1
+func (d *document) line(n int) string {
+	if n < 1 || n > len(d.lines) {
+		return ""
+	}
+	return d.lines[n-1]
+}
+
+// tokenSpan returns the span of tok in the document
+func (d *document) tokenSpan(tok token.Token) span {
+	end := tok.Pos
+	end.Column += len(source(tok))
+	return span{Start: d.position(tok.Pos), End: d.position(end)}
+}
+
+// utf16Len returns how many UTF-16 code units s is
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n++
+		if r >= 0x10000 {
+			// a surrogate pair
+			n++
+		}
+	}
+	return n
+}
+
+func before(a, b token.Position) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+}
+
+// identAt returns the identifier covering pos, or nil.
+func (d *document) identAt(pos token.Position) *ast.Identifier {
+	for _, ident := range d.idents {
+		start := ident.Token.Pos
+		if pos.Line == start.Line && pos.Column >= start.Column && pos.Column <= start.Column+len(ident.Value) {
+			return ident
+		}
+	}
+	return nil
+}
+
+// declaration returns the let statement name, or parameter ident refers to,
+// or nil if it is undefined.
+func (d *document) declaration(ident *ast.Identifier) *ast.Identifier {
+	if _, ok := d.params[ident]; ok {
+		return ident
+	}
+	if _, ok := d.lets[ident]; ok {
+		return ident
+	}
+	return d.decls[ident]
+}
+
+// references returns the uses of decl, ordered by position.
+func (d *document) references(decl *ast.Identifier) []*ast.Identifier {
+	var refs []*ast.Identifier
+	for _, ident := range d.idents {
+		if d.decls[ident] == decl {
+			refs = append(refs, ident)
+		}
+	}
+	return refs
+}
+
+func (d *document) diagnostics() []diagnostic {
+	severities := map[lint.Severity]int{
+		lint.Error:   severityError,
+		lint.Warning: severityWarning,
+		lint.Info:    severityInformation,
+	}
+
+	diags := []diagnostic{}
+	for _, f := range lint.Lint(d.text, lint.DefaultRules()) {
+		diags = append(diags, diagnostic{
+			Range:    d.spanAt(f.Pos),
+			Severity: severities[f.Severity],
+			Code:     f.Rule,
+			Source:   "monkey",
+			Message:  f.Msg,
+		})
+	}
+	return diags
+}
+
+// spanAt returns the span of the token starting at pos, or an empty span
+// at pos.
+func (d *document) spanAt(pos token.Position) span {
+	for _, tok := range d.tokens {
+		if tok.Pos == pos {
+			return d.tokenSpan(tok)
+		}
+	}
+	return span{Start: d.position(pos), End: d.position(pos)}
+}
+
+func (d *document) symbols() []documentSymbol {
+	syms := []documentSymbol{}
+	for _, ident := range d.idents {
+		let, ok := d.lets[ident]
+		if !ok {
+			continue
+		}
+		syms = append(syms, documentSymbol{
+			Name:           ident.Value,
+			Kind:           symbolKindVariable,
+			Range:          span{Start: d.position(let.Token.Pos), End: d.tokenSpan(ident.Token).End},
+			SelectionRange: d.tokenSpan(ident.Token),
+		})
+	}
+	return syms
+}
+
+// semanticTokenTypes is the legend of semantic token types. The index of a
+// type is its number in encoded tokens.
+var semanticTokenTypes = []string{"keyword", "variable", "number", "operator", "comment", "string"}
+
+func semanticTokenType(tok token.Token) int {
+	switch tok.Type {
+	case token.IDENT:
+		return 1
+	case token.INT:
+		return 2
+	case token.COMMENT:
+		return 4
+	case token.STRING, token.TEMPLATE_HEAD, token.TEMPLATE_MIDDLE, token.TEMPLATE_TAIL:
+		return 5
+	case token.ASSIGN, token.PLUS, token.MINUS, token.BANG, token.ASTERISK, token.SLASH,
+		token.LT, token.GT, token.EQ, token.NOT_EQ,
+		token.PLUS_ASSIGN, token.MINUS_ASSIGN, token.ASTERISK_ASSIGN, token.SLASH_ASSIGN,
+		token.ARROW, token.PIPE:
+		return 3
+	}
+	if token.IdentType(tok.Literal) == tok.Type {
+		return 0
+	}
+	return -1
+}
+
+// semanticTokens encodes d's tokens as the protocol wants: five numbers per
+// token, of its line and column relative to the previous token, its length,
+// type, and modifiers.
+func (d *document) semanticTokens() []int {
+	data := []int{}
+	var prev position
+	for _, tok := range d.tokens {
+		typ := semanticTokenType(tok)
+		if typ < 0 {
+			continue
+		}
+
+		s := d.tokenSpan(tok)
+		deltaLine, deltaChar := s.Start.Line-prev.Line, s.Start.Character
+		if deltaLine == 0 {
+			deltaChar -= prev.Character
+		}
+		data = append(data, deltaLine, deltaChar, s.End.Character-s.Start.Character, typ, 0)
+		prev = s.Start
+	}
+	return data
+}
@@ -0,0 +1,96 @@
+package lsp
+
+import (
+	"monkey/token"
+	"strings"
+)
+
+// format returns tokens as text, separated by single spaces, except where
+// punctuation and prefix operators hug their neighbours. Line breaks are
+// kept, with blank lines squashed to one, and lines are indented by how many
+// braces are open. The braces of hash types, at typeBraces' positions, hug
+// the types in them like brackets do, and don't indent.
+func format(tokens []token.Token, typeBraces map[token.Position]bool) string {
+	var b strings.Builder
+	depth := 0
+	var prev, prevValue token.Token // prevValue skips comments
+	prefix := false                 // is prev a prefix operator
+	var open []bool                 // is each open brace a hash type's
+
+	for i, tok := range tokens {
+		closesType := false
+		if tok.Type == token.RBRACE && len(open) > 0 {
+			closesType = open[len(open)-1]
+			open = open[:len(open)-1]
+		}
+		if tok.Type == token.RBRACE && depth > 0 && !closesType {
+			depth--
+		}
+		opensType := prev.Type == token.LBRACE && typeBraces[prev.Pos]
+
+		newLine := i == 0 || tok.Pos.Line > prev.Pos.Line
+		switch {
+		case i == 0:
+			b.WriteString(strings.Repeat("\t", depth))
+		case newLine:
+			lines := tok.Pos.Line - prev.Pos.Line
+			if lines > 2 {
+				lines = 2
+			}
+			b.WriteString(strings.Repeat("\n", lines))
+			b.WriteString(strings.Repeat("\t", depth))
+		case spaceBetween(prev, tok, prefix) && !opensType && !closesType:
+			b.WriteByte(' ')
+		}
+		b.WriteString(source(tok))
+
+		if tok.Type == token.LBRACE {
+			open = append(open, typeBraces[tok.Pos])
+			if !typeBraces[tok.Pos] {
+				depth++
+			}
+		}
+		if tok.Type != token.COMMENT {
+			prefix = (tok.Type == token.BANG || tok.Type == token.MINUS) && (newLine || !isValue(prevValue))
+			prevValue = tok
+		}
+		prev = tok
+	}
+
+	if b.Len() > 0 {
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// isValue is true if tok can end an operand, so an operator after it is infix
+func isValue(tok token.Token) bool {
+	switch tok.Type {
+	case token.IDENT, token.INT, token.STRING, token.TEMPLATE_TAIL, token.TRUE, token.FALSE, token.RPAREN:
+		return true
+	}
+	return false
+}
+
+func spaceBetween(prev, tok token.Token, prefix bool) bool {
+	switch {
+	case prefix:
+		return false
+	case tok.Type == token.SEMICOLON, tok.Type == token.COMMA, tok.Type == token.RPAREN,
+		tok.Type == token.COLON, tok.Type == token.RBRACKET:
+		return false
+	case prev.Type == token.LPAREN, prev.Type == token.LBRACKET:
+		return false
+	case prev.Type == token.TEMPLATE_HEAD, prev.Type == token.TEMPLATE_MIDDLE:
+		return false
+	case tok.Type == token.TEMPLATE_MIDDLE, tok.Type == token.TEMPLATE_TAIL:
+		return false
+	case tok.Type == token.LPAREN:
+		// calls, fn and macro literals, and quotes, but not if (...)
+		switch prev.Type {
+		case token.IDENT, token.FUNCTION, token.RPAREN, token.MACRO, token.QUOTE, token.UNQUOTE:
+			return false
+		}
+	}
+	return true
+}
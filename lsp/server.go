@@ -0,0 +1,294 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/parser"
+	"monkey/token"
+	"net/textproto"
+	"strconv"
+)
+
+// Server is a language server for Monkey, speaking JSON-RPC.
+type Server struct {
+	in   *bufio.Reader
+	out  io.Writer
+	docs map[string]*document // by uri
+}
+
+// NewServer creates a server reading requests from in, and writing responses
+// to out.
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{in: bufio.NewReader(in), out: out, docs: map[string]*document{}}
+}
+
+// Serve handles requests until the client sends exit, or in is closed.
+func (s *Server) Serve() error {
+	for {
+		body, err := s.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed reading request: %s", err)
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.write(errorResponse{JSONRPC: "2.0", Error: rpcError{Code: codeParseError, Message: err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if req.Method == "exit" {
+			return nil
+		}
+
+		result, rpcErr := s.handle(req)
+		if req.ID == nil {
+			// notifications get no response
+			continue
+		}
+
+		if rpcErr != nil {
+			err = s.write(errorResponse{JSONRPC: "2.0", ID: req.ID, Error: *rpcErr})
+		} else {
+			err = s.write(response{JSONRPC: "2.0", ID: req.ID, Result: result})
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// read returns the body of the next message. Messages are a header,
+// of which only Content-Length is used, a blank line, and a JSON body.
+func (s *Server) read() ([]byte, error) {
+	header, err := textproto.NewReader(s.in).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length: %s", err)
+	}
+	return ioutil.ReadAll(io.LimitReader(s.in, int64(length)))
+}
+
+func (s *Server) write(msg interface{}) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed encoding message: %s", err)
+	}
+	if _, err := fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		return fmt.Errorf("failed writing message: %s", err)
+	}
+	return nil
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return s.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// handle returns the result of req.
+func (s *Server) handle(req request) (interface{}, *rpcError) {
+	var decodeErr *rpcError
+	decode := func(params interface{}) bool {
+		if err := json.Unmarshal(req.Params, params); err != nil {
+			decodeErr = &rpcError{Code: codeInvalidParams, Message: err.Error()}
+			return false
+		}
+		return true
+	}
+
+	switch req.Method {
+	case "initialize":
+		return s.initialize(), nil
+	case "initialized", "shutdown", "$/cancelRequest":
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenTextDocumentParams
+		if decode(&params) {
+			s.open(params.TextDocument.URI, params.TextDocument.Text)
+		}
+		return nil, decodeErr
+	case "textDocument/didChange":
+		var params didChangeTextDocumentParams
+		// the server asks for full syncs, so the last change is the whole text
+		if decode(&params) && len(params.ContentChanges) > 0 {
+			s.open(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+		return nil, decodeErr
+	case "textDocument/didClose":
+		var params documentParams
+		if decode(&params) {
+			delete(s.docs, params.TextDocument.URI)
+		}
+		return nil, decodeErr
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if decode(&params) {
+			return s.hover(params), nil
+		}
+		return nil, decodeErr
+	case "textDocument/definition":
+		var params textDocumentPositionParams
+		if decode(&params) {
+			return s.definition(params), nil
+		}
+		return nil, decodeErr
+	case "textDocument/references":
+		var params referenceParams
+		if decode(&params) {
+			return s.references(params), nil
+		}
+		return nil, decodeErr
+	case "textDocument/documentSymbol":
+		var params documentParams
+		if decode(&params) {
+			return s.symbols(params.TextDocument.URI), nil
+		}
+		return nil, decodeErr
+	case "textDocument/semanticTokens/full":
+		var params documentParams
+		if decode(&params) {
+			return s.semanticTokens(params.TextDocument.URI), nil
+		}
+		return nil, decodeErr
+	case "textDocument/formatting":
+		var params documentParams
+		if decode(&params) {
+			return s.formatting(params.TextDocument.URI), nil
+		}
+		return nil, decodeErr
+	}
+
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+func (s *Server) initialize() interface{} {
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync":           1, // full
+			"hoverProvider":              true,
+			"definitionProvider":         true,
+			"referencesProvider":         true,
+			"documentSymbolProvider":     true,
+			"documentFormattingProvider": true,
+			"semanticTokensProvider": map[string]interface{}{
+				"legend": map[string]interface{}{
+					"tokenTypes":     semanticTokenTypes,
+					"tokenModifiers": []string{},
+				},
+				"full": true,
+			},
+		},
+		"serverInfo": map[string]string{"name": "monkey"},
+	}
+}
+
+// open parses text, and publishes its diagnostics
+func (s *Server) open(uri, text string) {
+	doc := newDocument(uri, text)
+	s.docs[uri] = doc
+	// a failed write will fail the next response too, and end Serve
+	s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: doc.diagnostics()})
+}
+
+// lookup returns the document, identifier and its declaration at params'
+// position. It returns a nil declaration if there is no variable there, or it
+// is undefined.
+func (s *Server) lookup(params textDocumentPositionParams) (*document, *ast.Identifier, *ast.Identifier) {
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return nil, nil, nil
+	}
+	ident := doc.identAt(doc.tokenPosition(params.Position))
+	if ident == nil {
+		return doc, nil, nil
+	}
+	return doc, ident, doc.declaration(ident)
+}
+
+func (s *Server) hover(params textDocumentPositionParams) interface{} {
+	doc, ident, decl := s.lookup(params)
+	if decl == nil {
+		return nil
+	}
+
+	code := doc.params[decl]
+	if let, ok := doc.lets[decl]; ok {
+		code = let.String()
+	}
+	return hover{
+		Contents: markupContent{Kind: "markdown", Value: "```monkey\n" + code + "\n```"},
+		Range:    doc.tokenSpan(ident.Token),
+	}
+}
+
+func (s *Server) definition(params textDocumentPositionParams) interface{} {
+	doc, _, decl := s.lookup(params)
+	if decl == nil {
+		return nil
+	}
+	return location{URI: doc.uri, Range: doc.tokenSpan(decl.Token)}
+}
+
+func (s *Server) references(params referenceParams) []location {
+	locs := []location{}
+	doc, _, decl := s.lookup(params.textDocumentPositionParams)
+	if decl == nil {
+		return locs
+	}
+
+	if params.Context.IncludeDeclaration {
+		locs = append(locs, location{URI: doc.uri, Range: doc.tokenSpan(decl.Token)})
+	}
+	for _, ref := range doc.references(decl) {
+		locs = append(locs, location{URI: doc.uri, Range: doc.tokenSpan(ref.Token)})
+	}
+	return locs
+}
+
+func (s *Server) symbols(uri string) []documentSymbol {
+	if doc, ok := s.docs[uri]; ok {
+		return doc.symbols()
+	}
+	return []documentSymbol{}
+}
+
+func (s *Server) semanticTokens(uri string) semanticTokens {
+	if doc, ok := s.docs[uri]; ok {
+		return semanticTokens{Data: doc.semanticTokens()}
+	}
+	return semanticTokens{Data: []int{}}
+}
+
+// formatting returns an edit replacing the document with its formatted text.
+// Documents that don't parse are left alone.
+func (s *Server) formatting(uri string) []textEdit {
+	edits := []textEdit{}
+	doc, ok := s.docs[uri]
+	if !ok {
+		return edits
+	}
+	if _, err := parser.New(lexer.New(doc.text)).Parse(); err != nil {
+		return edits
+	}
+
+	formatted := format(doc.tokens, doc.typeBraces)
+	if formatted == doc.text {
+		return edits
+	}
+
+	last := len(doc.lines)
+	end := doc.position(token.Position{Line: last, Column: len(doc.line(last)) + 1})
+	return append(edits, textEdit{Range: span{End: end}, NewText: formatted})
+}
@@ -0,0 +1,351 @@
+package lsp_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"monkey/lsp"
+	"net/textproto"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// fakeClient talks to a server running in the same process.
+type fakeClient struct {
+	t      *testing.T
+	in     io.WriteCloser // to the server
+	out    *bufio.Reader  // from the server
+	done   chan error
+	nextID int
+}
+
+func newFakeClient(t *testing.T) *fakeClient {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &fakeClient{t: t, in: inW, out: bufio.NewReader(outR), done: make(chan error, 1)}
+
+	go func() {
+		c.done <- lsp.NewServer(inR, outW).Serve()
+	}()
+	return c
+}
+
+func (c *fakeClient) send(msg interface{}) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+type message struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+func (c *fakeClient) receive() message {
+	header, err := textproto.NewReader(c.out).ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(c.out, int64(length)))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.t.Fatalf("failed decoding %s: %s", body, err)
+	}
+	return msg
+}
+
+// call sends a request, and decodes its result into result.
+func (c *fakeClient) call(method string, params, result interface{}) {
+	c.nextID++
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+
+	msg := c.receive()
+	if msg.ID == nil || *msg.ID != c.nextID {
+		c.t.Fatalf("have message %+v, want response to %s", msg, method)
+	}
+	if msg.Error != nil {
+		c.t.Fatalf("have error code %v for %s", msg.Error.Code, method)
+	}
+	if err := json.Unmarshal(msg.Result, result); err != nil {
+		c.t.Fatalf("failed decoding %s result %s: %s", method, msg.Result, err)
+	}
+}
+
+// open sends didOpen, and returns the published diagnostics' messages.
+func (c *fakeClient) open(uri, text string) []string {
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "version": 1, "text": text},
+	}})
+
+	msg := c.receive()
+	if msg.Method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("have message %+v, want diagnostics", msg)
+	}
+	var params struct {
+		Diagnostics []struct {
+			Message string `json:"message"`
+		} `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		c.t.Fatal(err)
+	}
+
+	msgs := []string{}
+	for _, d := range params.Diagnostics {
+		msgs = append(msgs, d.Message)
+	}
+	return msgs
+}
+
+func (c *fakeClient) exit() {
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "method": "exit"})
+	if err := <-c.done; err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+type position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type span struct {
+	Start position `json:"start"`
+	End   position `json:"end"`
+}
+
+func at(uri string, line, char int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]string{"uri": uri},
+		"position":     position{Line: line, Character: char},
+	}
+}
+
+const uri = "file:///test.mk"
+
+const text = `let one = 1;
+let two = -one;
+two;
+`
+
+func TestServerDiagnostics(t *testing.T) {
+	c := newFakeClient(t)
+	defer c.exit()
+
+	var init struct {
+		Capabilities map[string]interface{} `json:"capabilities"`
+	}
+	c.call("initialize", map[string]interface{}{}, &init)
+	if init.Capabilities["hoverProvider"] != true {
+		t.Fatalf("have capabilities %v, want hoverProvider", init.Capabilities)
+	}
+
+	have := c.open(uri, "let = 1;")
+	want := []string{"have next token type =, want IDENT"}
+	if !reflect.DeepEqual(have, want) {
+		t.Fatalf("have diagnostics %v, want %v", have, want)
+	}
+
+	have = c.open(uri, text)
+	if len(have) != 0 {
+		t.Fatalf("have diagnostics %v, want none", have)
+	}
+}
+
+func TestServerNavigation(t *testing.T) {
+	c := newFakeClient(t)
+	defer c.exit()
+	c.open(uri, text)
+
+	var hover struct {
+		Contents struct {
+			Value string `json:"value"`
+		} `json:"contents"`
+	}
+	c.call("textDocument/hover", at(uri, 1, 12), &hover)
+	if want := "```monkey\nlet one = 1;\n```"; hover.Contents.Value != want {
+		t.Fatalf("have hover %q, want %q", hover.Contents.Value, want)
+	}
+
+	var def struct {
+		Range span `json:"range"`
+	}
+	c.call("textDocument/definition", at(uri, 2, 1), &def)
+	if want := (span{Start: position{1, 4}, End: position{1, 7}}); def.Range != want {
+		t.Fatalf("have definition %+v, want %+v", def.Range, want)
+	}
+
+	var refs []struct {
+		Range span `json:"range"`
+	}
+	params := at(uri, 0, 5)
+	params["context"] = map[string]bool{"includeDeclaration": true}
+	c.call("textDocument/references", params, &refs)
+	if len(refs) != 2 || refs[0].Range.Start != (position{0, 4}) || refs[1].Range.Start != (position{1, 11}) {
+		t.Fatalf("have references %+v, want one:1:5, and 2:12", refs)
+	}
+
+	var syms []struct {
+		Name string `json:"name"`
+	}
+	c.call("textDocument/documentSymbol", map[string]interface{}{"textDocument": map[string]string{"uri": uri}}, &syms)
+	if len(syms) != 2 || syms[0].Name != "one" || syms[1].Name != "two" {
+		t.Fatalf("have symbols %+v, want one, and two", syms)
+	}
+}
+
+func TestServerSemanticTokens(t *testing.T) {
+	c := newFakeClient(t)
+	defer c.exit()
+	c.open(uri, "let one = 1; // c\n-one;\n\"a${one}b\";")
+
+	var toks struct {
+		Data []int `json:"data"`
+	}
+	c.call("textDocument/semanticTokens/full", map[string]interface{}{"textDocument": map[string]string{"uri": uri}}, &toks)
+	want := []int{
+		0, 0, 3, 0, 0, // let
+		0, 4, 3, 1, 0, // one
+		0, 4, 1, 3, 0, // =
+		0, 2, 1, 2, 0, // 1
+		0, 3, 4, 4, 0, // // c
+		1, 0, 1, 3, 0, // -
+		0, 1, 3, 1, 0, // one
+		1, 0, 4, 5, 0, // "a${
+		0, 4, 3, 1, 0, // one
+		0, 3, 3, 5, 0, // }b"
+	}
+	if !reflect.DeepEqual(toks.Data, want) {
+		t.Fatalf("have semantic tokens %v, want %v", toks.Data, want)
+	}
+}
+
+// Positions count UTF-16 code units: é is one, and 😀 two, though they're
+// two, and four bytes.
+func TestServerUTF16(t *testing.T) {
+	c := newFakeClient(t)
+	defer c.exit()
+	c.open(uri, "let s = \"é😀\"; s; // ü\ns;")
+
+	var toks struct {
+		Data []int `json:"data"`
+	}
+	c.call("textDocument/semanticTokens/full", map[string]interface{}{"textDocument": map[string]string{"uri": uri}}, &toks)
+	want := []int{
+		0, 0, 3, 0, 0, // let
+		0, 4, 1, 1, 0, // s
+		0, 2, 1, 3, 0, // =
+		0, 2, 5, 5, 0, // "é😀"
+		0, 7, 1, 1, 0, // s
+		0, 3, 4, 4, 0, // // ü
+		1, 0, 1, 1, 0, // s
+	}
+	if !reflect.DeepEqual(toks.Data, want) {
+		t.Fatalf("have semantic tokens %v, want %v", toks.Data, want)
+	}
+
+	var def struct {
+		Range span `json:"range"`
+	}
+	c.call("textDocument/definition", at(uri, 0, 15), &def)
+	if want := (span{Start: position{0, 4}, End: position{0, 5}}); def.Range != want {
+		t.Fatalf("have definition %+v, want %+v", def.Range, want)
+	}
+
+	var refs []struct {
+		Range span `json:"range"`
+	}
+	c.call("textDocument/references", at(uri, 1, 0), &refs)
+	if len(refs) != 2 || refs[0].Range != (span{Start: position{0, 15}, End: position{0, 16}}) {
+		t.Fatalf("have references %+v, want s at 0:15, and 1:0", refs)
+	}
+}
+
+func TestServerFormatting(t *testing.T) {
+	c := newFakeClient(t)
+	defer c.exit()
+	c.open(uri, "let   one=1 ;\n\n\n\n- one;// c\nreturn ! one;")
+
+	var edits []struct {
+		Range   span   `json:"range"`
+		NewText string `json:"newText"`
+	}
+	c.call("textDocument/formatting", map[string]interface{}{"textDocument": map[string]string{"uri": uri}}, &edits)
+
+	want := "let one = 1;\n\n-one; // c\nreturn !one;\n"
+	if len(edits) != 1 || edits[0].NewText != want {
+		t.Fatalf("have edits %+v, want %q", edits, want)
+	}
+	if end := (position{5, 13}); edits[0].Range.End != end {
+		t.Fatalf("have edit end %+v, want %+v", edits[0].Range.End, end)
+	}
+}
+
+func TestServerFormattingTypes(t *testing.T) {
+	c := newFakeClient(t)
+	defer c.exit()
+	c.open(uri, "let  m :{ string :[ int ] }=x ;\nif (x) {\nlet f: fn( { int: int } ) -> bool = g; }\nlet h=fn(a :{ int: int }) -> [ int ] {a};")
+
+	var edits []struct {
+		NewText string `json:"newText"`
+	}
+	c.call("textDocument/formatting", map[string]interface{}{"textDocument": map[string]string{"uri": uri}}, &edits)
+
+	want := "let m: {string: [int]} = x;\nif (x) {\n\tlet f: fn({int: int}) -> bool = g; }\nlet h = fn(a: {int: int}) -> [int] { a };\n"
+	if len(edits) != 1 || edits[0].NewText != want {
+		t.Fatalf("have edits %+v, want %q", edits, want)
+	}
+}
+
+func TestServerFunctions(t *testing.T) {
+	c := newFakeClient(t)
+	defer c.exit()
+	c.open(uri, "let add=fn( a,b ){a+b};\n1|>add( 2 )|>add(_,3);")
+
+	var hover struct {
+		Contents struct {
+			Value string `json:"value"`
+		} `json:"contents"`
+	}
+	c.call("textDocument/hover", at(uri, 0, 18), &hover)
+	if want := "```monkey\nfn(a, b)\n```"; hover.Contents.Value != want {
+		t.Fatalf("have hover %q, want %q", hover.Contents.Value, want)
+	}
+
+	var def struct {
+		Range span `json:"range"`
+	}
+	c.call("textDocument/definition", at(uri, 0, 20), &def)
+	if want := (span{Start: position{0, 14}, End: position{0, 15}}); def.Range != want {
+		t.Fatalf("have definition %+v, want %+v", def.Range, want)
+	}
+
+	var edits []struct {
+		NewText string `json:"newText"`
+	}
+	c.call("textDocument/formatting", map[string]interface{}{"textDocument": map[string]string{"uri": uri}}, &edits)
+	want := "let add = fn(a, b) { a + b };\n1 |> add(2) |> add(_, 3);\n"
+	if len(edits) != 1 || edits[0].NewText != want {
+		t.Fatalf("have edits %+v, want %q", edits, want)
+	}
+}
@@ -1,13 +1,39 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"monkey/lsp"
 	"monkey/repl"
 	"os"
 	"os/user"
 )
 
 func main() {
+	noOptimize := flag.Bool("no-optimize", false, "don't fold constants or drop unreachable code, for debugging")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: monkey [flags] [vet file... | check file... | lsp]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	switch flag.Arg(0) {
+	case "vet":
+		os.Exit(vet(flag.Args()[1:]))
+	case "check":
+		os.Exit(check(flag.Args()[1:]))
+	case "lsp":
+		if err := lsp.NewServer(os.Stdin, os.Stdout).Serve(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "":
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	usr, err := user.Current()
 	if err != nil {
 		log.Printf("failed reading current os user = %+v\n", err)
@@ -15,5 +41,5 @@ func main() {
 
 	log.Printf("Hello %s. Welcome to the Monkey REPL!", usr.Username)
 
-	repl.Start(os.Stdin, os.Stdout)
+	repl.Start(os.Stdin, os.Stdout, !*noOptimize)
 }
@@ -0,0 +1,160 @@
+package module
+
+import (
+	"fmt"
+	"io/ioutil"
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/parser"
+	"monkey/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Ext is the extension of module files. Import paths can leave it out.
+const Ext = parser.ModuleExt
+
+// Module is a parsed file, and the modules it imports.
+type Module struct {
+	File    string // absolute path
+	Program *ast.Program
+	Imports map[string]*Module // by the name they're bound to
+	Exports map[string]*ast.LetStatement
+}
+
+// Error is a module that failed to load.
+type Error struct {
+	File string
+	Pos  token.Position // of the failing import, if any
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Pos == (token.Position{}) {
+		return fmt.Sprintf("%s: %s", e.File, e.Err)
+	}
+	return fmt.Sprintf("%s:%s: %s", e.File, e.Pos, e.Err)
+}
+
+// cycleError is a module importing itself, through the modules in chain.
+type cycleError struct {
+	chain string
+}
+
+func (e cycleError) Error() string {
+	return "import cycle: " + e.chain
+}
+
+// Loader loads modules, and the modules they import, once each.
+type Loader struct {
+	// Path is the directories searched for imports that aren't found
+	// relative to the importing file.
+	Path []string
+
+	modules map[string]*Module // by absolute file path
+	loading []string           // files being loaded, importers first
+}
+
+// NewLoader creates a loader searching path for imports.
+func NewLoader(path ...string) *Loader {
+	return &Loader{Path: path, modules: map[string]*Module{}}
+}
+
+// Load parses file, and every module it imports. Modules already loaded are
+// returned from the loader's cache. The returned error is an *Error.
+func (l *Loader) Load(file string) (*Module, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, &Error{File: file, Err: err}
+	}
+
+	if mod, ok := l.modules[abs]; ok {
+		return mod, nil
+	}
+	for i, f := range l.loading {
+		if f == abs {
+			// the importer fills in where the cycle is closed
+			return nil, &Error{Err: cycleError{chain: l.chain(i, abs)}}
+		}
+	}
+
+	b, err := ioutil.ReadFile(abs)
+	if err != nil {
+		return nil, &Error{File: file, Err: err}
+	}
+	prog, err := parser.New(lexer.New(string(b))).Parse()
+	if err != nil {
+		return nil, &Error{File: file, Err: err}
+	}
+
+	l.loading = append(l.loading, abs)
+	defer func() { l.loading = l.loading[:len(l.loading)-1] }()
+
+	mod := &Module{File: abs, Program: prog, Imports: map[string]*Module{}, Exports: map[string]*ast.LetStatement{}}
+	for _, stmt := range prog.Statements {
+		switch stmt := stmt.(type) {
+		case *ast.ExportStatement:
+			mod.Exports[stmt.Let.Name.Value] = stmt.Let
+		case *ast.ImportStatement:
+			imported, err := l.loadImport(abs, stmt)
+			if err != nil {
+				return nil, err
+			}
+			mod.Imports[stmt.Name.Value] = imported
+		}
+	}
+
+	l.modules[abs] = mod
+	return mod, nil
+}
+
+func (l *Loader) loadImport(importer string, stmt *ast.ImportStatement) (*Module, error) {
+	file, err := l.find(filepath.Dir(importer), stmt.Path.Value)
+	if err != nil {
+		return nil, &Error{File: importer, Pos: stmt.Token.Pos, Err: err}
+	}
+
+	mod, err := l.Load(file)
+	if err != nil {
+		e := err.(*Error)
+		if _, ok := e.Err.(cycleError); ok {
+			// cycles are reported once, at the import closing them
+			if e.File == "" {
+				e.File, e.Pos = importer, stmt.Token.Pos
+			}
+			return nil, e
+		}
+		return nil, &Error{File: importer, Pos: stmt.Token.Pos, Err: fmt.Errorf("failed importing %q: %s", stmt.Path.Value, err)}
+	}
+	return mod, nil
+}
+
+// find returns the file of an import path, looking in dir, and then the
+// loader's path.
+func (l *Loader) find(dir, path string) (string, error) {
+	if filepath.Ext(path) != Ext {
+		path += Ext
+	}
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+
+	dirs := append([]string{dir}, l.Path...)
+	for _, d := range dirs {
+		file := filepath.Join(d, filepath.FromSlash(path))
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		}
+	}
+	return "", fmt.Errorf("cannot find module %q in %s", path, strings.Join(dirs, ", "))
+}
+
+// chain returns the import chain from l.loading[from], back to file
+func (l *Loader) chain(from int, file string) string {
+	var names []string
+	for _, f := range append(l.loading[from:], file) {
+		names = append(names, filepath.Base(f))
+	}
+	return strings.Join(names, " -> ")
+}
@@ -0,0 +1,85 @@
+package module_test
+
+import (
+	"io/ioutil"
+	"monkey/module"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates files, by path relative to a temporary directory, and
+// returns the directory.
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "module")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, text := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"main.mk":        `import "lib/util"; import "shared.mk"; util;`,
+		"lib/util.mk":    `import "shared"; export let answer = 42; let hidden = 1;`,
+		"path/shared.mk": `export const one = 1;`,
+	})
+	defer os.RemoveAll(dir)
+
+	l := module.NewLoader(filepath.Join(dir, "path"))
+	mod, err := l.Load(filepath.Join(dir, "main.mk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	util, ok := mod.Imports["util"]
+	if !ok {
+		t.Fatalf("have imports %v, want util", mod.Imports)
+	}
+	if len(util.Exports) != 1 || util.Exports["answer"] == nil {
+		t.Fatalf("have util exports %v, want answer", util.Exports)
+	}
+
+	// shared is imported twice, but loaded once
+	if mod.Imports["shared"] == nil || mod.Imports["shared"] != util.Imports["shared"] {
+		t.Fatalf("have shared modules %p and %p, want the same one", mod.Imports["shared"], util.Imports["shared"])
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.mk":       `import "b";`,
+		"b.mk":       "let x = 1;\nimport \"c\";",
+		"c.mk":       `import "a";`,
+		"missing.mk": `import "nope";`,
+		"broken.mk":  `import "bad";`,
+		"bad.mk":     `let = 1;`,
+	})
+	defer os.RemoveAll(dir)
+
+	tests := map[string]string{
+		"a.mk":       "c.mk:1:1: import cycle: a.mk -> b.mk -> c.mk -> a.mk",
+		"missing.mk": `missing.mk:1:1: cannot find module "nope.mk" in ` + dir,
+		"broken.mk":  `broken.mk:1:1: failed importing "bad": ` + filepath.Join(dir, "bad.mk") + ": 1:1: have next token type =, want IDENT",
+	}
+
+	for file, want := range tests {
+		_, err := module.NewLoader().Load(filepath.Join(dir, file))
+		if err == nil {
+			t.Fatalf("have no error loading %s, want %s", file, want)
+		}
+		if have := strings.TrimPrefix(err.Error(), dir+string(filepath.Separator)); have != want {
+			t.Fatalf("have error %s loading %s, want %s", have, file, want)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package optimizer
+
+import (
+	"math"
+	"monkey/ast"
+	"monkey/token"
+	"strconv"
+)
+
+// Optimize folds constant expressions in prog, and drops statements that can
+// never run. prog is rewritten in place.
+func Optimize(prog *ast.Program) {
+	prog.Statements = optimizeStatements(prog.Statements)
+}
+
+func optimizeStatements(stmts []ast.Statement) []ast.Statement {
+	var out []ast.Statement
+	for _, stmt := range stmts {
+		opt := optimizeStatement(stmt)
+		if opt == nil {
+			// a branch that never runs
+			continue
+		}
+
+		opts := []ast.Statement{opt}
+		if block, ok := opt.(*ast.BlockStatement); ok && block != stmt && !declares(block) {
+			// the taken branch of an if statement runs as part of this
+			// block, unless its bindings would leak into it
+			opts = block.Statements
+		}
+		for _, opt := range opts {
+			out = append(out, opt)
+
+			// nothing after a statement that jumps out of its block is
+			// reachable
+			switch opt.(type) {
+			case *ast.ReturnStatement, *ast.ThrowStatement, *ast.BreakStatement, *ast.ContinueStatement:
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// declares is true if block binds variables in its scope
+func declares(block *ast.BlockStatement) bool {
+	for _, stmt := range block.Statements {
+		switch stmt.(type) {
+		case *ast.LetStatement, *ast.ExportStatement:
+			return true
+		}
+	}
+	return false
+}
+
+func optimizeStatement(stmt ast.Statement) ast.Statement {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		stmt.Value = fold(stmt.Value)
+	case *ast.ReturnStatement:
+		stmt.Value = fold(stmt.Value)
+	case *ast.ExportStatement:
+		optimizeStatement(stmt.Let)
+	case *ast.ThrowStatement:
+		stmt.Value = fold(stmt.Value)
+	case *ast.ExpressionStatement:
+		stmt.Expression = fold(stmt.Expression)
+		if ifExp, ok := stmt.Expression.(*ast.IfExpression); ok {
+			return takenBranch(stmt, ifExp)
+		}
+	case *ast.BlockStatement:
+		optimizeBlock(stmt)
+	case *ast.WhileStatement:
+		stmt.Condition = fold(stmt.Condition)
+		optimizeBlock(stmt.Body)
+		if cond, ok := stmt.Condition.(*ast.Boolean); ok && !cond.Value {
+			return nil
+		}
+	case *ast.ForStatement:
+		stmt.Iterable = fold(stmt.Iterable)
+		optimizeBlock(stmt.Body)
+	case *ast.TryStatement:
+		optimizeBlock(stmt.Body)
+		optimizeBlock(stmt.Catch)
+		optimizeBlock(stmt.Finally)
+	case *ast.SelectStatement:
+		for _, c := range stmt.Cases {
+			fold(c.Operation)
+			optimizeBlock(c.Body)
+		}
+		optimizeBlock(stmt.Default)
+	}
+	return stmt
+}
+
+// takenBranch returns the block an if statement with a constant condition
+// always runs, or nil if it runs neither. Other if statements are returned
+// as they are.
+func takenBranch(stmt *ast.ExpressionStatement, ifExp *ast.IfExpression) ast.Statement {
+	cond, ok := ifExp.Condition.(*ast.Boolean)
+	switch {
+	case !ok:
+		return stmt
+	case cond.Value:
+		return ifExp.Consequence
+	case ifExp.Alternative != nil:
+		return ifExp.Alternative
+	}
+	return nil
+}
+
+func optimizeBlock(block *ast.BlockStatement) {
+	if block != nil {
+		block.Statements = optimizeStatements(block.Statements)
+	}
+}
+
+// fold returns expr with constant sub expressions replaced by their value.
+// Folded values start where the expressions they replace did.
+func fold(expr ast.Expression) ast.Expression {
+	switch expr := expr.(type) {
+	case *ast.AssignExpression:
+		expr.Value = fold(expr.Value)
+	case *ast.TemplateLiteral:
+		for i, e := range expr.Expressions {
+			expr.Expressions[i] = fold(e)
+		}
+	case *ast.FunctionLiteral:
+		optimizeBlock(expr.Body)
+	case *ast.CallExpression:
+		expr.Function = fold(expr.Function)
+		for i, arg := range expr.Arguments {
+			expr.Arguments[i] = fold(arg)
+		}
+	case *ast.SpawnExpression:
+		expr.Call = fold(expr.Call)
+	case *ast.MacroLiteral:
+		optimizeBlock(expr.Body)
+	case *ast.PrefixExpression:
+		expr.Expression = fold(expr.Expression)
+		return foldPrefix(expr)
+	case *ast.InfixExpression:
+		expr.Left = fold(expr.Left)
+		expr.Right = fold(expr.Right)
+		return foldInfix(expr)
+	case *ast.IfExpression:
+		expr.Condition = fold(expr.Condition)
+		optimizeBlock(expr.Consequence)
+		optimizeBlock(expr.Alternative)
+		if cond, ok := expr.Condition.(*ast.Boolean); ok {
+			return foldIf(expr, cond.Value)
+		}
+	}
+	return expr
+}
+
+// foldIf returns the value of the branch an if expression with a constant
+// condition always runs, if it is a single expression. Otherwise the branch
+// that never runs is emptied.
+func foldIf(expr *ast.IfExpression, cond bool) ast.Expression {
+	taken := expr.Alternative
+	if cond {
+		taken = expr.Consequence
+	}
+	if taken != nil && len(taken.Statements) == 1 {
+		if stmt, ok := taken.Statements[0].(*ast.ExpressionStatement); ok {
+			return stmt.Expression
+		}
+	}
+
+	if cond {
+		expr.Alternative = nil
+	} else {
+		expr.Consequence.Statements = nil
+	}
+	return expr
+}
+
+func foldPrefix(expr *ast.PrefixExpression) ast.Expression {
+	switch operand := expr.Expression.(type) {
+	case *ast.Integer:
+		if expr.Operator == token.MINUS && operand.Value != math.MinInt64 {
+			return integer(-operand.Value, expr.Token.Pos)
+		}
+	case *ast.Boolean:
+		if expr.Operator == token.BANG {
+			return boolean(!operand.Value, expr.Token.Pos)
+		}
+	}
+	return expr
+}
+
+func foldInfix(expr *ast.InfixExpression) ast.Expression {
+	pos := ast.Pos(expr)
+	switch left := expr.Left.(type) {
+	case *ast.Integer:
+		right, ok := expr.Right.(*ast.Integer)
+		if !ok {
+			break
+		}
+		if val, ok := arithmetic(expr.Operator, left.Value, right.Value); ok {
+			return integer(val, pos)
+		}
+		switch expr.Operator {
+		case token.LT:
+			return boolean(left.Value < right.Value, pos)
+		case token.GT:
+			return boolean(left.Value > right.Value, pos)
+		case token.EQ:
+			return boolean(left.Value == right.Value, pos)
+		case token.NOT_EQ:
+			return boolean(left.Value != right.Value, pos)
+		}
+	case *ast.Boolean:
+		right, ok := expr.Right.(*ast.Boolean)
+		if !ok {
+			break
+		}
+		switch expr.Operator {
+		case token.EQ:
+			return boolean(left.Value == right.Value, pos)
+		case token.NOT_EQ:
+			return boolean(left.Value != right.Value, pos)
+		}
+	case *ast.StringLiteral:
+		right, ok := expr.Right.(*ast.StringLiteral)
+		if !ok {
+			break
+		}
+		switch expr.Operator {
+		case token.PLUS:
+			val := left.Value + right.Value
+			return &ast.StringLiteral{Token: token.Token{Type: token.STRING, Literal: val, Pos: pos}, Value: val}
+		case token.EQ:
+			return boolean(left.Value == right.Value, pos)
+		case token.NOT_EQ:
+			return boolean(left.Value != right.Value, pos)
+		}
+	}
+	return expr
+}
+
+// arithmetic returns a op b. ok is false if op isn't arithmetic, or the
+// result would overflow, or divide by zero, which are left for runtime.
+func arithmetic(op string, a, b int64) (val int64, ok bool) {
+	switch op {
+	case token.PLUS:
+		val = a + b
+		return val, (val > a) == (b > 0)
+	case token.MINUS:
+		val = a - b
+		return val, (val < a) == (b > 0)
+	case token.ASTERISK:
+		if a == 0 || b == 0 {
+			return 0, true
+		}
+		val = a * b
+		return val, val/b == a && !(a == -1 && b == math.MinInt64) && !(b == -1 && a == math.MinInt64)
+	case token.SLASH:
+		if b == 0 || (a == math.MinInt64 && b == -1) {
+			return 0, false
+		}
+		return a / b, true
+	}
+	return 0, false
+}
+
+func integer(val int64, pos token.Position) *ast.Integer {
+	tok := token.Token{Type: token.INT, Literal: strconv.FormatInt(val, 10), Pos: pos}
+	return &ast.Integer{Token: tok, Value: val}
+}
+
+func boolean(val bool, pos token.Position) *ast.Boolean {
+	tok := token.Token{Type: token.FALSE, Literal: "false", Pos: pos}
+	if val {
+		tok = token.Token{Type: token.TRUE, Literal: "true", Pos: pos}
+	}
+	return &ast.Boolean{Token: tok, Value: val}
+}
@@ -0,0 +1,165 @@
+package optimizer_test
+
+import (
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/optimizer"
+	"monkey/parser"
+	"monkey/token"
+	"testing"
+)
+
+func TestOptimizeFoldsNegation(t *testing.T) {
+	input := `-5; --7; let foo = -10; !-3; -foo;`
+	want := []string{"-5", "7", "let foo = -10;", "(!) -3", "(-) foo"}
+
+	par := parser.New(lexer.New(input))
+	prog, err := par.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	optimizer.Optimize(prog)
+
+	if len(prog.Statements) != len(want) {
+		t.Fatalf("have %v statements, want %v", len(prog.Statements), len(want))
+	}
+
+	for i, stmt := range prog.Statements {
+		if stmt.String() != want[i] {
+			t.Fatalf("have statement %s, want %s", stmt.String(), want[i])
+		}
+	}
+
+	stmt := prog.Statements[1].(*ast.ExpressionStatement)
+	num, ok := stmt.Expression.(*ast.Integer)
+	if !ok {
+		t.Fatalf("have expression type %T, want %T", stmt.Expression, &ast.Integer{})
+	}
+	if num.Value != 7 {
+		t.Fatalf("have integer value %v, want %v", num.Value, 7)
+	}
+	if want := (token.Position{Line: 1, Column: 5}); num.Token.Pos != want {
+		t.Fatalf("have integer position %s, want %s", num.Token.Pos, want)
+	}
+}
+
+func TestOptimizeDropsUnreachable(t *testing.T) {
+	input := `let foo = 1; return foo; foo; let bar = 2;`
+
+	par := parser.New(lexer.New(input))
+	prog, err := par.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	optimizer.Optimize(prog)
+
+	if len(prog.Statements) != 2 {
+		t.Fatalf("have %v statements, want %v", len(prog.Statements), 2)
+	}
+	if _, ok := prog.Statements[1].(*ast.ReturnStatement); !ok {
+		t.Fatalf("have last statement type %T, want %T", prog.Statements[1], &ast.ReturnStatement{})
+	}
+}
+
+func TestOptimizeBlocks(t *testing.T) {
+	input := `try { throw --1; foo; } catch (err) { return err; err; }`
+	want := "try { throw 1 } catch (err) { return err }"
+
+	par := parser.New(lexer.New(input))
+	prog, err := par.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	optimizer.Optimize(prog)
+
+	if prog.String() != want {
+		t.Fatalf("have program %s, want %s", prog.String(), want)
+	}
+}
+
+func TestOptimizeFoldsInfix(t *testing.T) {
+	input := `1 + 2 * 3; 10 / 3 - 4; 1 < 2 == true; !(1 > 2); "a" + "b" == "ab"; x + 1 * 2; 1 / 0; 9223372036854775807 + 1;`
+	want := []string{"7", "-1", "true", "true", "true", "(x + 2)", "(1 / 0)", "(9223372036854775807 + 1)"}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	optimizer.Optimize(prog)
+
+	if len(prog.Statements) != len(want) {
+		t.Fatalf("have %v statements, want %v", len(prog.Statements), len(want))
+	}
+	for i, stmt := range prog.Statements {
+		if stmt.String() != want[i] {
+			t.Fatalf("have statement %s, want %s", stmt.String(), want[i])
+		}
+	}
+
+	// folded values start where the expression did
+	stmt := prog.Statements[3].(*ast.ExpressionStatement)
+	b, ok := stmt.Expression.(*ast.Boolean)
+	if !ok {
+		t.Fatalf("have expression type %T, want %T", stmt.Expression, &ast.Boolean{})
+	}
+	if want := (token.Position{Line: 1, Column: 39}); b.Token.Pos != want {
+		t.Fatalf("have boolean position %s, want %s", b.Token.Pos, want)
+	}
+}
+
+func TestOptimizeDropsDeadBranches(t *testing.T) {
+	input := `if (1 > 2) { a; } if (false) { a; } else { b; } if (true) { c; } else { d; } while (false) { e; } if (x) { f; }
+let y = if (true) { 1 } else { 2 };`
+	want := "b\nc\nif x { f }\nlet y = 1;"
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	optimizer.Optimize(prog)
+
+	if prog.String() != want {
+		t.Fatalf("have program %s, want %s", prog.String(), want)
+	}
+}
+
+func TestOptimizeFunctions(t *testing.T) {
+	input := `let f = fn(x) { return x * (2 + 3); x; };
+f(1 + 1) |> g(-(-4));`
+	want := "let f = fn(x) { return (x * 5) };\ng(f(2), 4)"
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	optimizer.Optimize(prog)
+
+	if prog.String() != want {
+		t.Fatalf("have program %s, want %s", prog.String(), want)
+	}
+}
+
+func TestOptimizeFoldsIfs(t *testing.T) {
+	input := `let a = if (false) { 1 } else { 2 };
+let b = if (1 > 2) { 1 };
+let c = if (true) { let d = 1; d } else { 3 };
+let f = fn() { if (true) { 1 } else { 2 } };
+let g = fn() { if (2 > 1) { a; return b; } c; };
+if (true) { let h = 1; h; }`
+	want := `let a = 2;
+let b = if false {  };
+let c = if true { let d = 1; d };
+let f = fn() { 1 };
+let g = fn() { a return b };
+{ let h = 1; h }`
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	optimizer.Optimize(prog)
+
+	if prog.String() != want {
+		t.Fatalf("have program %s, want %s", prog.String(), want)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"monkey/ast"
 	"monkey/lexer"
 	"monkey/token"
+	"path"
 	"strconv"
+	"strings"
 )
 
 // Parser makes statements and expressions from a lexer's tokens
@@ -14,6 +16,10 @@ type Parser struct {
 	currTok token.Token
 	nextTok token.Token
 	errors  []string
+	loops   int // how many loops the current token is in
+	quotes  int // how many quotes the current token is in, less the unquotes
+	blocks  int // how many blocks the current token is in
+	braces  int // how many braces are open, counting the current token
 }
 
 // New creates a parser
@@ -27,16 +33,56 @@ func New(l *lexer.Lexer) *Parser {
 func (p *Parser) readToken() {
 	p.currTok = p.nextTok
 	p.nextTok = p.l.NextToken()
+
+	switch p.currTok.Type {
+	case token.LBRACE:
+		p.braces++
+	case token.RBRACE:
+		p.braces--
+	}
+}
+
+// expectNext reads the next token, if it has type typ.
+func (p *Parser) expectNext(typ token.Type) error {
+	if p.nextTok.Type != typ {
+		return fmt.Errorf("have next token type %s, want %s", p.nextTok.Type, typ)
+	}
+	p.readToken()
+	return nil
+}
+
+// Error is a statement that could not be parsed
+type Error struct {
+	Pos token.Position // where the statement starts
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Err)
+}
+
+// ErrorList is every statement in a program that could not be parsed
+type ErrorList []*Error
+
+func (el ErrorList) Error() string {
+	var ss []string
+	for _, e := range el {
+		ss = append(ss, e.Error())
+	}
+	return strings.Join(ss, "\n")
 }
 
-// Parse reads lexer's tokens, and creates AST Nodes from them
+// Parse reads lexer's tokens, and creates AST Nodes from them.
+// The returned error is an ErrorList.
 func (p *Parser) Parse() (*ast.Program, error) {
 	pro := &ast.Program{}
-	var err error
+	var errs ErrorList
 	for p.currTok.Type != token.EOF {
+		start, braces := p.currTok, p.braces
 		stmt, stmtErr := p.parseStatement()
 		if stmtErr != nil {
-			err = fmt.Errorf("%s\n%s", err, stmtErr)
+			errs = append(errs, &Error{Pos: start.Pos, Err: stmtErr})
+			p.skipStatement(start, braces)
 			continue
 		}
 		if stmt != nil {
@@ -45,15 +91,75 @@ func (p *Parser) Parse() (*ast.Program, error) {
 		p.readToken()
 	}
 
-	return pro, err
+	if len(errs) > 0 {
+		return pro, errs
+	}
+	return pro, nil
+}
+
+// skipStatement skips the rest of a statement that failed parsing from start,
+// when braces were open: up to the closing brace of a block it opened, or
+// else its semicolon, the end of the line it failed on, or the next statement
+// keyword.
+func (p *Parser) skipStatement(start token.Token, braces int) {
+	if p.braces > braces {
+		for p.currTok.Type != token.EOF && p.braces > braces {
+			p.readToken()
+		}
+		// the block's closing brace
+		p.readToken()
+		return
+	}
+
+	line := p.currTok.Pos.Line
+	// skip the token that failed, so it isn't parsed again
+	if p.currTok == start {
+		p.readToken()
+	}
+
+	for p.currTok.Type != token.EOF && p.currTok.Pos.Line == line && !startsStatement(p.currTok.Type) {
+		typ := p.currTok.Type
+		p.readToken()
+		if typ == token.SEMICOLON {
+			return
+		}
+	}
+}
+
+func startsStatement(typ token.Type) bool {
+	switch typ {
+	case token.LET, token.CONST, token.RETURN, token.THROW, token.TRY, token.WHILE, token.FOR,
+		token.BREAK, token.CONTINUE, token.IMPORT, token.EXPORT, token.IF, token.SELECT:
+		return true
+	}
+	return false
 }
 
 func (p *Parser) parseStatement() (ast.Statement, error) {
 	switch p.currTok.Type {
-	case token.LET:
+	case token.LET, token.CONST:
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.TRY:
+		return p.parseTryStatement()
+	case token.WHILE:
+		return p.parseWhileStatement()
+	case token.FOR:
+		return p.parseForStatement()
+	case token.BREAK, token.CONTINUE:
+		return p.parseLoopControlStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.EXPORT:
+		return p.parseExportStatement()
+	case token.SELECT:
+		return p.parseSelectStatement()
+	case token.SEMICOLON:
+		// an empty statement
+		return nil, nil
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -79,31 +185,508 @@ func (p *Parser) parsePrefixExpression() (ast.Expression, error) {
 	return preExp, nil
 }
 
+// operator precedences, from loosest to tightest binding
+const (
+	_ int = iota
+	lowest
+	pipe        // |>
+	equals      // ==
+	lessGreater // < or >
+	sum         // +
+	product     // *
+	prefix      // -x or !x
+	call        // f(x)
+)
+
+var precedences = map[token.Type]int{
+	token.EQ:       equals,
+	token.NOT_EQ:   equals,
+	token.LT:       lessGreater,
+	token.GT:       lessGreater,
+	token.PLUS:     sum,
+	token.MINUS:    sum,
+	token.ASTERISK: product,
+	token.SLASH:    product,
+	token.PIPE:     pipe,
+	token.LPAREN:   call,
+}
+
+// parseExpression parses an expression, ending on its last token.
 func (p *Parser) parseExpression() (ast.Expression, error) {
-	var expr ast.Expression
+	return p.parseOperand(lowest)
+}
+
+// parseOperand parses an expression, and the infix operators after it that
+// bind tighter than prec.
+func (p *Parser) parseOperand(prec int) (ast.Expression, error) {
+	expr, err := p.parseRawOperand(prec)
+	if err != nil {
+		return nil, err
+	}
+	return partial(expr), nil
+}
+
+// parseRawOperand is parseOperand, leaving a call with placeholder arguments
+// as it is, for a pipe to fill in.
+func (p *Parser) parseRawOperand(prec int) (ast.Expression, error) {
+	left, err := p.parsePrefix(prec)
+	if err != nil {
+		return nil, err
+	}
+
+	for prec < precedences[p.nextTok.Type] {
+		p.readToken()
+		if left, err = p.parseInfix(partial(left)); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// parseInfix parses the operator that is the current token, and what
+// follows it.
+func (p *Parser) parseInfix(left ast.Expression) (ast.Expression, error) {
+	switch p.currTok.Type {
+	case token.LPAREN:
+		return p.parseCallExpression(left)
+	case token.PIPE:
+		return p.parsePipe(left)
+	}
+	return p.parseInfixExpression(left)
+}
+
+// parsePrefix parses an expression that doesn't start with an operand.
+func (p *Parser) parsePrefix(prec int) (ast.Expression, error) {
 	switch p.currTok.Type {
 	case token.BANG, token.MINUS:
 		preExp := ast.PrefixExpression{Token: p.currTok, Operator: p.currTok.Literal}
 		p.readToken()
-		exp, err := p.parseExpression()
+		exp, err := p.parseOperand(prefix)
 		if err != nil {
 			return nil, fmt.Errorf("failed parsing prefix expression's expression: %s", err)
 		}
 		preExp.Expression = exp
 		return &preExp, nil
 	case token.IDENT:
-		expr = &ast.Identifier{Token: p.currTok, Value: p.currTok.Literal}
+		if p.currTok.Literal == placeholder {
+			return nil, fmt.Errorf("have %s outside of call arguments", placeholder)
+		}
+		ident := &ast.Identifier{Token: p.currTok, Value: p.currTok.Literal}
+		// a + b = 5 isn't an assignment to b
+		if isAssignment(p.nextTok.Type) && prec == lowest {
+			return p.parseAssignExpression(ident)
+		}
+		return ident, nil
 	case token.INT:
 		num, err := strconv.ParseInt(p.currTok.Literal, 0, 64)
 		if err != nil {
-			return &ast.Identifier{}, fmt.Errorf("could not parse token: %v into int64: %s", p.currTok, err)
+			return nil, fmt.Errorf("could not parse token: %v into int64: %s", p.currTok, err)
+		}
+		return &ast.Integer{Token: p.currTok, Value: num}, nil
+	case token.TRUE, token.FALSE:
+		return &ast.Boolean{Token: p.currTok, Value: p.currTok.Type == token.TRUE}, nil
+	case token.STRING:
+		return &ast.StringLiteral{Token: p.currTok, Value: p.currTok.Literal}, nil
+	case token.TEMPLATE_HEAD:
+		return p.parseTemplateLiteral()
+	case token.LPAREN:
+		p.readToken()
+		// x |> (f(1, _)) is x |> f(1, _)
+		expr, err := p.parseRawOperand(lowest)
+		if err != nil {
+			return nil, err
 		}
+		if err := p.expectNext(token.RPAREN); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	case token.IF:
+		return p.parseIfExpression()
+	case token.FUNCTION:
+		return p.parseFunctionLiteral()
+	case token.SPAWN:
+		return p.parseSpawnExpression()
+	case token.MACRO:
+		return p.parseMacroLiteral()
+	case token.QUOTE:
+		return p.parseQuoteExpression()
+	case token.UNQUOTE:
+		return p.parseUnquoteExpression()
+	case token.ILLEGAL:
+		return nil, fmt.Errorf("have illegal token %q", p.currTok.Literal)
+	}
+	return nil, fmt.Errorf("have token type %s, want an expression", p.currTok.Type)
+}
 
-		expr = &ast.Integer{Token: p.currTok, Value: num}
+func (p *Parser) parseInfixExpression(left ast.Expression) (*ast.InfixExpression, error) {
+	expr := &ast.InfixExpression{Token: p.currTok, Left: left, Operator: p.currTok.Literal}
+	prec := precedences[p.currTok.Type]
+	p.readToken()
+
+	// operators of the same precedence are left associative: the right
+	// operand only takes tighter ones
+	right, err := p.parseOperand(prec)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing right operand of %s: %s", expr.Operator, err)
 	}
+	expr.Right = right
+
 	return expr, nil
 }
 
+// parseFunctionLiteral parses a function's parameters, their optional
+// annotations, and body, ending on the body's closing brace.
+func (p *Parser) parseFunctionLiteral() (*ast.FunctionLiteral, error) {
+	fn := &ast.FunctionLiteral{Token: p.currTok}
+	if err := p.expectNext(token.LPAREN); err != nil {
+		return nil, fmt.Errorf("failed parsing function parameters: %s", err)
+	}
+	p.readToken()
+
+	for p.currTok.Type != token.RPAREN {
+		if p.currTok.Type != token.IDENT || p.currTok.Literal == placeholder {
+			return nil, fmt.Errorf("have token %q in function parameters, want a name", p.currTok.Literal)
+		}
+		fn.Parameters = append(fn.Parameters, &ast.Identifier{Token: p.currTok, Value: p.currTok.Literal})
+		p.readToken()
+
+		var typ ast.TypeExpression
+		if p.currTok.Type == token.COLON {
+			p.readToken()
+			var err error
+			if typ, err = p.parseType(); err != nil {
+				return nil, fmt.Errorf("failed parsing parameter type: %s", err)
+			}
+			p.readToken()
+		}
+		fn.Types = append(fn.Types, typ)
+
+		if p.currTok.Type == token.COMMA {
+			p.readToken()
+		} else if p.currTok.Type != token.RPAREN {
+			return nil, fmt.Errorf("have token type %s in function parameters, want %s", p.currTok.Type, token.RPAREN)
+		}
+	}
+
+	if p.nextTok.Type == token.ARROW {
+		p.readToken()
+		p.readToken()
+		result, err := p.parseType()
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing result type: %s", err)
+		}
+		fn.Result = result
+	}
+
+	if err := p.expectNext(token.LBRACE); err != nil {
+		return nil, fmt.Errorf("failed parsing function body: %s", err)
+	}
+	// break, and continue can't jump out of a function, into the loop
+	// around it
+	loops := p.loops
+	p.loops = 0
+	defer func() { p.loops = loops }()
+
+	body, err := p.parseBlockStatement()
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing function body: %s", err)
+	}
+	fn.Body = body
+	return fn, nil
+}
+
+// parseMacroLiteral parses a macro's parameters, and body, ending on the
+// body's closing brace.
+func (p *Parser) parseMacroLiteral() (*ast.MacroLiteral, error) {
+	macro := &ast.MacroLiteral{Token: p.currTok}
+	if err := p.expectNext(token.LPAREN); err != nil {
+		return nil, fmt.Errorf("failed parsing macro parameters: %s", err)
+	}
+	p.readToken()
+
+	for p.currTok.Type != token.RPAREN {
+		if p.currTok.Type != token.IDENT || p.currTok.Literal == placeholder {
+			return nil, fmt.Errorf("have token %q in macro parameters, want a name", p.currTok.Literal)
+		}
+		macro.Parameters = append(macro.Parameters, &ast.Identifier{Token: p.currTok, Value: p.currTok.Literal})
+		p.readToken()
+
+		if p.currTok.Type == token.COMMA {
+			p.readToken()
+		} else if p.currTok.Type != token.RPAREN {
+			return nil, fmt.Errorf("have token type %s in macro parameters, want %s", p.currTok.Type, token.RPAREN)
+		}
+	}
+
+	if err := p.expectNext(token.LBRACE); err != nil {
+		return nil, fmt.Errorf("failed parsing macro body: %s", err)
+	}
+	loops := p.loops
+	p.loops = 0
+	defer func() { p.loops = loops }()
+
+	body, err := p.parseBlockStatement()
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing macro body: %s", err)
+	}
+	macro.Body = body
+	return macro, nil
+}
+
+// parseQuoted parses the parenthesized expression after quote, or unquote,
+// ending on the closing parenthesis.
+func (p *Parser) parseQuoted() (ast.Expression, error) {
+	keyword := p.currTok.Literal
+	if err := p.expectNext(token.LPAREN); err != nil {
+		return nil, fmt.Errorf("failed parsing %s: %s", keyword, err)
+	}
+	p.readToken()
+
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing %s: %s", keyword, err)
+	}
+	if err := p.expectNext(token.RPAREN); err != nil {
+		return nil, fmt.Errorf("failed parsing %s: %s", keyword, err)
+	}
+	return expr, nil
+}
+
+func (p *Parser) parseQuoteExpression() (*ast.QuoteExpression, error) {
+	expr := &ast.QuoteExpression{Token: p.currTok}
+	p.quotes++
+	defer func() { p.quotes-- }()
+
+	node, err := p.parseQuoted()
+	if err != nil {
+		return nil, err
+	}
+	expr.Node = node
+	return expr, nil
+}
+
+// parseUnquoteExpression parses an unquote, which can only be in a quote.
+// What it unquotes isn't quoted.
+func (p *Parser) parseUnquoteExpression() (*ast.UnquoteExpression, error) {
+	expr := &ast.UnquoteExpression{Token: p.currTok}
+	if p.quotes == 0 {
+		return nil, fmt.Errorf("have %s outside of a quote", expr.Token.Literal)
+	}
+	p.quotes--
+	defer func() { p.quotes++ }()
+
+	value, err := p.parseQuoted()
+	if err != nil {
+		return nil, err
+	}
+	expr.Expression = value
+	return expr, nil
+}
+
+// placeholder is an argument left out of a call, making a function of the
+// arguments left out: f(_, 2) is a function of one argument, x, calling
+// f(x, 2)
+const placeholder = "_"
+
+// parseCallExpression parses the arguments of a call to fn, ending on the
+// closing parenthesis. Placeholder arguments are left in the call, as
+// identifiers named _.
+func (p *Parser) parseCallExpression(fn ast.Expression) (*ast.CallExpression, error) {
+	expr := &ast.CallExpression{Token: p.currTok, Function: fn}
+	p.readToken()
+
+	for p.currTok.Type != token.RPAREN {
+		if p.currTok.Literal == placeholder && (p.nextTok.Type == token.COMMA || p.nextTok.Type == token.RPAREN) {
+			expr.Arguments = append(expr.Arguments, &ast.Identifier{Token: p.currTok, Value: placeholder})
+		} else {
+			arg, err := p.parseExpression()
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing call argument: %s", err)
+			}
+			expr.Arguments = append(expr.Arguments, arg)
+		}
+		p.readToken()
+
+		if p.currTok.Type == token.COMMA {
+			p.readToken()
+		} else if p.currTok.Type != token.RPAREN {
+			return nil, fmt.Errorf("have token type %s in call arguments, want %s", p.currTok.Type, token.RPAREN)
+		}
+	}
+
+	return expr, nil
+}
+
+func isPlaceholder(expr ast.Expression) bool {
+	ident, ok := expr.(*ast.Identifier)
+	return ok && ident.Value == placeholder
+}
+
+// partial returns expr, or if it is a call with placeholder arguments, a
+// function of them. The function called, and its other arguments are
+// evaluated once, when the partial function is made, by passing them to a
+// function that returns it:
+// f(_, x, 2) is fn(_1, _2) { fn(_3) { _1(_3, _2, 2) } }(f, x)
+func partial(expr ast.Expression) ast.Expression {
+	call, ok := expr.(*ast.CallExpression)
+	if !ok {
+		return expr
+	}
+	holes := 0
+	for _, arg := range call.Arguments {
+		if isPlaceholder(arg) {
+			holes++
+		}
+	}
+	if holes == 0 {
+		return expr
+	}
+
+	// digits can't be in names, so these can't clash with the program's
+	// own. The names aren't in the source, so their tokens' literals are _.
+	var names int
+	name := func(pos token.Position) (param, use *ast.Identifier) {
+		names++
+		tok := token.Token{Type: token.IDENT, Literal: placeholder, Pos: pos}
+		value := placeholder + strconv.Itoa(names)
+		return &ast.Identifier{Token: tok, Value: value}, &ast.Identifier{Token: tok, Value: value}
+	}
+
+	outer := &ast.FunctionLiteral{Token: token.Token{Type: token.FUNCTION, Literal: "fn", Pos: ast.Pos(call)}}
+	inner := &ast.FunctionLiteral{Token: outer.Token}
+	made := &ast.CallExpression{Token: call.Token}
+	captured := &ast.CallExpression{Token: call.Token, Function: outer}
+	capture := func(expr ast.Expression) ast.Expression {
+		param, use := name(ast.Pos(expr))
+		outer.Parameters = append(outer.Parameters, param)
+		outer.Types = append(outer.Types, nil)
+		captured.Arguments = append(captured.Arguments, expr)
+		return use
+	}
+
+	made.Function = capture(call.Function)
+	for _, arg := range call.Arguments {
+		switch arg.(type) {
+		case *ast.Integer, *ast.StringLiteral, *ast.Boolean:
+			// constants are the same whenever they're evaluated
+			made.Arguments = append(made.Arguments, arg)
+		default:
+			if !isPlaceholder(arg) {
+				arg = capture(arg)
+			}
+			made.Arguments = append(made.Arguments, arg)
+		}
+	}
+	for i, arg := range made.Arguments {
+		if isPlaceholder(arg) {
+			param, use := name(ast.Pos(arg))
+			inner.Parameters = append(inner.Parameters, param)
+			inner.Types = append(inner.Types, nil)
+			made.Arguments[i] = use
+		}
+	}
+
+	inner.Body = block(call.Token, made)
+	outer.Body = block(call.Token, inner)
+	return captured
+}
+
+// block returns a block of the statement expr, starting at tok
+func block(tok token.Token, expr ast.Expression) *ast.BlockStatement {
+	return &ast.BlockStatement{
+		Token:      token.Token{Type: token.LBRACE, Literal: "{", Pos: tok.Pos},
+		Statements: []ast.Statement{&ast.ExpressionStatement{Token: tok, Expression: expr}},
+	}
+}
+
+// parsePipe parses the call after a |>, passing left as its first argument:
+// x |> f(a) is f(x, a). In a call with placeholders, left is passed in place
+// of the first: x |> f(a, _) is f(a, x). A function literal after it is
+// called with left.
+func (p *Parser) parsePipe(left ast.Expression) (ast.Expression, error) {
+	tok := p.currTok
+	p.readToken()
+	right, err := p.parseRawOperand(pipe)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing right operand of %s: %s", token.PIPE, err)
+	}
+
+	switch right := right.(type) {
+	case *ast.CallExpression:
+		for i, arg := range right.Arguments {
+			if isPlaceholder(arg) {
+				right.Arguments[i] = left
+				return right, nil
+			}
+		}
+		right.Arguments = append([]ast.Expression{left}, right.Arguments...)
+		return right, nil
+	case *ast.FunctionLiteral:
+		return &ast.CallExpression{Token: tok, Function: right, Arguments: []ast.Expression{left}}, nil
+	}
+	return nil, fmt.Errorf("have %s after %s, want a call", right, token.PIPE)
+}
+
+// parseIfExpression parses an if, and its optional else block, ending on the
+// last block's closing brace.
+func (p *Parser) parseIfExpression() (*ast.IfExpression, error) {
+	expr := &ast.IfExpression{Token: p.currTok}
+
+	if err := p.expectNext(token.LPAREN); err != nil {
+		return nil, fmt.Errorf("failed parsing if condition: %s", err)
+	}
+	p.readToken()
+
+	cond, err := p.parseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing if condition: %s", err)
+	}
+	expr.Condition = cond
+
+	if err := p.expectNext(token.RPAREN); err != nil {
+		return nil, fmt.Errorf("failed parsing if condition: %s", err)
+	}
+	if err := p.expectNext(token.LBRACE); err != nil {
+		return nil, fmt.Errorf("failed parsing if block: %s", err)
+	}
+	if expr.Consequence, err = p.parseBlockStatement(); err != nil {
+		return nil, fmt.Errorf("failed parsing if block: %s", err)
+	}
+
+	if p.nextTok.Type == token.ELSE {
+		p.readToken()
+		if err := p.expectNext(token.LBRACE); err != nil {
+			return nil, fmt.Errorf("failed parsing else block: %s", err)
+		}
+		if expr.Alternative, err = p.parseBlockStatement(); err != nil {
+			return nil, fmt.Errorf("failed parsing else block: %s", err)
+		}
+	}
+
+	return expr, nil
+}
+
+// parseTemplateLiteral parses a string with interpolations, ending on its
+// TEMPLATE_TAIL.
+func (p *Parser) parseTemplateLiteral() (*ast.TemplateLiteral, error) {
+	tmpl := &ast.TemplateLiteral{Token: p.currTok, Parts: []string{p.currTok.Literal}}
+	for p.currTok.Type != token.TEMPLATE_TAIL {
+		p.readToken()
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing interpolated expression: %s", err)
+		}
+		tmpl.Expressions = append(tmpl.Expressions, expr)
+
+		if p.nextTok.Type != token.TEMPLATE_MIDDLE && p.nextTok.Type != token.TEMPLATE_TAIL {
+			return nil, fmt.Errorf("have next token type %s in interpolation, want }", p.nextTok.Type)
+		}
+		p.readToken()
+		tmpl.Parts = append(tmpl.Parts, p.currTok.Literal)
+	}
+	return tmpl, nil
+}
+
 func (p *Parser) parseExpressionStatement() (*ast.ExpressionStatement, error) {
 	stmt := ast.ExpressionStatement{Token: p.currTok}
 	expr, err := p.parseExpression()
@@ -144,7 +727,7 @@ func (p *Parser) parseReturnStatement() (*ast.ReturnStatement, error) {
 func (p *Parser) parseLetStatement() (*ast.LetStatement, error) {
 	stmt := ast.LetStatement{Token: p.currTok}
 
-	if p.currTok.Type != token.LET {
+	if p.currTok.Type != token.LET && p.currTok.Type != token.CONST {
 		return &ast.LetStatement{}, fmt.Errorf("have token type %s in beginning of let statement, want %s", p.currTok.Type, token.LET)
 	}
 	p.readToken()
@@ -155,6 +738,16 @@ func (p *Parser) parseLetStatement() (*ast.LetStatement, error) {
 	stmt.Name = &ast.Identifier{Token: p.currTok, Value: p.currTok.Literal}
 	p.readToken()
 
+	if p.currTok.Type == token.COLON {
+		p.readToken()
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing type of %s: %s", stmt.Name.Value, err)
+		}
+		stmt.Type = typ
+		p.readToken()
+	}
+
 	if p.currTok.Type != token.ASSIGN {
 		return nil, fmt.Errorf("have next token type %s, want %s", p.currTok.Type, token.ASSIGN)
 	}
@@ -173,3 +766,434 @@ func (p *Parser) parseLetStatement() (*ast.LetStatement, error) {
 
 	return &stmt, nil
 }
+
+// parseType parses a type annotation, ending on its last token.
+func (p *Parser) parseType() (ast.TypeExpression, error) {
+	switch p.currTok.Type {
+	case token.IDENT:
+		return &ast.NamedType{Token: p.currTok, Name: p.currTok.Literal}, nil
+	case token.LBRACKET:
+		typ := &ast.ArrayType{Token: p.currTok}
+		p.readToken()
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		typ.Elem = elem
+		if err := p.expectNext(token.RBRACKET); err != nil {
+			return nil, err
+		}
+		return typ, nil
+	case token.LBRACE:
+		typ := &ast.HashType{Token: p.currTok}
+		p.readToken()
+		key, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		typ.Key = key
+		if err := p.expectNext(token.COLON); err != nil {
+			return nil, err
+		}
+		p.readToken()
+		value, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		typ.Value = value
+		if err := p.expectNext(token.RBRACE); err != nil {
+			return nil, err
+		}
+		return typ, nil
+	case token.FUNCTION:
+		return p.parseFuncType()
+	}
+	return nil, fmt.Errorf("have token type %s, want a type", p.currTok.Type)
+}
+
+func (p *Parser) parseFuncType() (*ast.FuncType, error) {
+	typ := &ast.FuncType{Token: p.currTok}
+	if err := p.expectNext(token.LPAREN); err != nil {
+		return nil, err
+	}
+	p.readToken()
+
+	for p.currTok.Type != token.RPAREN {
+		param, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		typ.Params = append(typ.Params, param)
+		p.readToken()
+
+		if p.currTok.Type == token.COMMA {
+			p.readToken()
+		} else if p.currTok.Type != token.RPAREN {
+			return nil, fmt.Errorf("have token type %s in parameter types, want %s", p.currTok.Type, token.RPAREN)
+		}
+	}
+
+	if err := p.expectNext(token.ARROW); err != nil {
+		return nil, err
+	}
+	p.readToken()
+	result, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	typ.Result = result
+	return typ, nil
+}
+
+func (p *Parser) parseThrowStatement() (*ast.ThrowStatement, error) {
+	stmt := ast.ThrowStatement{Token: p.currTok}
+	p.readToken()
+
+	expr, err := p.parseExpression()
+	if err != nil {
+		return &ast.ThrowStatement{}, fmt.Errorf("failed parsing expression in throw statement: %s", err)
+	}
+	stmt.Value = expr
+
+	if p.nextTok.Type != token.SEMICOLON {
+		return nil, fmt.Errorf("have token %v, want %s", p.nextTok.Type, token.SEMICOLON)
+	}
+	p.readToken()
+
+	return &stmt, nil
+}
+
+// parseBlockStatement parses statements until the closing brace, which is
+// left as the current token.
+func (p *Parser) parseBlockStatement() (*ast.BlockStatement, error) {
+	block := ast.BlockStatement{Token: p.currTok}
+	if p.currTok.Type != token.LBRACE {
+		return nil, fmt.Errorf("have token type %s in beginning of block, want %s", p.currTok.Type, token.LBRACE)
+	}
+	p.readToken()
+
+	p.blocks++
+	defer func() { p.blocks-- }()
+
+	for p.currTok.Type != token.RBRACE {
+		if p.currTok.Type == token.EOF {
+			return nil, fmt.Errorf("have token type %s in block, want %s", p.currTok.Type, token.RBRACE)
+		}
+
+		stmt, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.readToken()
+	}
+
+	return &block, nil
+}
+
+func (p *Parser) parseTryStatement() (*ast.TryStatement, error) {
+	stmt := ast.TryStatement{Token: p.currTok}
+
+	if err := p.expectNext(token.LBRACE); err != nil {
+		return nil, fmt.Errorf("failed parsing try block: %s", err)
+	}
+	body, err := p.parseBlockStatement()
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing try block: %s", err)
+	}
+	stmt.Body = body
+
+	if p.nextTok.Type == token.CATCH {
+		p.readToken()
+		for _, typ := range []token.Type{token.LPAREN, token.IDENT} {
+			if err := p.expectNext(typ); err != nil {
+				return nil, fmt.Errorf("failed parsing catch parameter: %s", err)
+			}
+		}
+		stmt.Param = &ast.Identifier{Token: p.currTok, Value: p.currTok.Literal}
+		for _, typ := range []token.Type{token.RPAREN, token.LBRACE} {
+			if err := p.expectNext(typ); err != nil {
+				return nil, fmt.Errorf("failed parsing catch parameter: %s", err)
+			}
+		}
+
+		if stmt.Catch, err = p.parseBlockStatement(); err != nil {
+			return nil, fmt.Errorf("failed parsing catch block: %s", err)
+		}
+	}
+
+	if p.nextTok.Type == token.FINALLY {
+		p.readToken()
+		if err := p.expectNext(token.LBRACE); err != nil {
+			return nil, fmt.Errorf("failed parsing finally block: %s", err)
+		}
+		if stmt.Finally, err = p.parseBlockStatement(); err != nil {
+			return nil, fmt.Errorf("failed parsing finally block: %s", err)
+		}
+	}
+
+	if stmt.Catch == nil && stmt.Finally == nil {
+		return nil, fmt.Errorf("have next token type %s after try block, want %s or %s", p.nextTok.Type, token.CATCH, token.FINALLY)
+	}
+
+	return &stmt, nil
+}
+
+// parseSpawnExpression parses the call after spawn, ending on its last token.
+func (p *Parser) parseSpawnExpression() (*ast.SpawnExpression, error) {
+	expr := &ast.SpawnExpression{Token: p.currTok}
+	p.readToken()
+
+	call, err := p.parseOperand(prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing spawned call: %s", err)
+	}
+	switch call.(type) {
+	case *ast.CallExpression, *ast.FunctionLiteral:
+	default:
+		return nil, fmt.Errorf("have %s after %s, want a call, or function literal", call, expr.Token.Literal)
+	}
+	expr.Call = call
+	return expr, nil
+}
+
+// parseSelectStatement parses a select's cases, ending on its closing brace.
+func (p *Parser) parseSelectStatement() (*ast.SelectStatement, error) {
+	stmt := ast.SelectStatement{Token: p.currTok}
+	if err := p.expectNext(token.LBRACE); err != nil {
+		return nil, fmt.Errorf("failed parsing select: %s", err)
+	}
+	p.readToken()
+
+	for p.currTok.Type != token.RBRACE {
+		switch p.currTok.Type {
+		case token.CASE:
+			c, err := p.parseSelectCase()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Cases = append(stmt.Cases, c)
+		case token.DEFAULT:
+			if stmt.Default != nil {
+				return nil, fmt.Errorf("have a second default in select")
+			}
+			if err := p.expectNext(token.LBRACE); err != nil {
+				return nil, fmt.Errorf("failed parsing select default: %s", err)
+			}
+			block, err := p.parseBlockStatement()
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing select default: %s", err)
+			}
+			stmt.Default = block
+		default:
+			return nil, fmt.Errorf("have token type %s in select, want %s or %s", p.currTok.Type, token.CASE, token.DEFAULT)
+		}
+		p.readToken()
+	}
+
+	return &stmt, nil
+}
+
+// parseSelectCase parses a case's channel operation, which is a call, and the
+// name it binds, ex: case v = recv(ch) { }, ending on the case's closing
+// brace.
+func (p *Parser) parseSelectCase() (*ast.SelectCase, error) {
+	c := &ast.SelectCase{Token: p.currTok}
+	p.readToken()
+
+	if p.currTok.Type == token.IDENT && p.nextTok.Type == token.ASSIGN {
+		c.Name = &ast.Identifier{Token: p.currTok, Value: p.currTok.Literal}
+		p.readToken()
+		p.readToken()
+	}
+
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing select case: %s", err)
+	}
+	call, ok := expr.(*ast.CallExpression)
+	if !ok {
+		return nil, fmt.Errorf("have %s in select case, want a channel operation call", expr)
+	}
+	c.Operation = call
+
+	if err := p.expectNext(token.LBRACE); err != nil {
+		return nil, fmt.Errorf("failed parsing select case: %s", err)
+	}
+	if c.Body, err = p.parseBlockStatement(); err != nil {
+		return nil, fmt.Errorf("failed parsing select case: %s", err)
+	}
+	return c, nil
+}
+
+// parseLoopBody parses a loop's block, allowing break and continue in it.
+func (p *Parser) parseLoopBody() (*ast.BlockStatement, error) {
+	if err := p.expectNext(token.LBRACE); err != nil {
+		return nil, err
+	}
+
+	p.loops++
+	defer func() { p.loops-- }()
+	return p.parseBlockStatement()
+}
+
+func (p *Parser) parseWhileStatement() (*ast.WhileStatement, error) {
+	stmt := ast.WhileStatement{Token: p.currTok}
+
+	if err := p.expectNext(token.LPAREN); err != nil {
+		return nil, fmt.Errorf("failed parsing while condition: %s", err)
+	}
+	p.readToken()
+
+	cond, err := p.parseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing while condition: %s", err)
+	}
+	stmt.Condition = cond
+
+	if err := p.expectNext(token.RPAREN); err != nil {
+		return nil, fmt.Errorf("failed parsing while condition: %s", err)
+	}
+
+	if stmt.Body, err = p.parseLoopBody(); err != nil {
+		return nil, fmt.Errorf("failed parsing while body: %s", err)
+	}
+
+	return &stmt, nil
+}
+
+func (p *Parser) parseForStatement() (*ast.ForStatement, error) {
+	stmt := ast.ForStatement{Token: p.currTok}
+
+	for _, typ := range []token.Type{token.LPAREN, token.IDENT} {
+		if err := p.expectNext(typ); err != nil {
+			return nil, fmt.Errorf("failed parsing for variable: %s", err)
+		}
+	}
+	stmt.Variable = &ast.Identifier{Token: p.currTok, Value: p.currTok.Literal}
+
+	if err := p.expectNext(token.IN); err != nil {
+		return nil, fmt.Errorf("failed parsing for variable: %s", err)
+	}
+	p.readToken()
+
+	iter, err := p.parseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing for iterable: %s", err)
+	}
+	stmt.Iterable = iter
+
+	if err := p.expectNext(token.RPAREN); err != nil {
+		return nil, fmt.Errorf("failed parsing for iterable: %s", err)
+	}
+
+	if stmt.Body, err = p.parseLoopBody(); err != nil {
+		return nil, fmt.Errorf("failed parsing for body: %s", err)
+	}
+
+	return &stmt, nil
+}
+
+// parseLoopControlStatement parses break, and continue statements, which
+// can only be in loops.
+func (p *Parser) parseLoopControlStatement() (ast.Statement, error) {
+	tok := p.currTok
+	if p.loops == 0 {
+		return nil, fmt.Errorf("have %s outside of a loop", tok.Literal)
+	}
+
+	if p.nextTok.Type != token.SEMICOLON {
+		return nil, fmt.Errorf("have token %v, want %s", p.nextTok.Type, token.SEMICOLON)
+	}
+	p.readToken()
+
+	if tok.Type == token.BREAK {
+		return &ast.BreakStatement{Token: tok}, nil
+	}
+	return &ast.ContinueStatement{Token: tok}, nil
+}
+
+func isAssignment(typ token.Type) bool {
+	switch typ {
+	case token.ASSIGN, token.PLUS_ASSIGN, token.MINUS_ASSIGN, token.ASTERISK_ASSIGN, token.SLASH_ASSIGN:
+		return true
+	}
+	return false
+}
+
+// parseAssignExpression parses the operator after name, and the assigned
+// value. Assignments are right associative: a = b = 5 assigns b first.
+func (p *Parser) parseAssignExpression(name *ast.Identifier) (*ast.AssignExpression, error) {
+	p.readToken()
+	expr := ast.AssignExpression{Token: p.currTok, Name: name, Operator: p.currTok.Literal}
+	p.readToken()
+
+	val, err := p.parseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing assigned value: %s", err)
+	}
+	expr.Value = val
+
+	return &expr, nil
+}
+
+func (p *Parser) parseImportStatement() (*ast.ImportStatement, error) {
+	stmt := ast.ImportStatement{Token: p.currTok}
+	if p.blocks > 0 {
+		return nil, fmt.Errorf("have %s in a block, want it at top level", stmt.Token.Literal)
+	}
+
+	if err := p.expectNext(token.STRING); err != nil {
+		return nil, fmt.Errorf("failed parsing import path: %s", err)
+	}
+	stmt.Path = &ast.StringLiteral{Token: p.currTok, Value: p.currTok.Literal}
+
+	name := ImportName(stmt.Path.Value)
+	if !isIdentifier(name) {
+		return nil, fmt.Errorf("have import path %q, want it to end in a variable name", stmt.Path.Value)
+	}
+	stmt.Name = &ast.Identifier{Token: token.Token{Type: token.IDENT, Literal: name, Pos: p.currTok.Pos}, Value: name}
+
+	if p.nextTok.Type != token.SEMICOLON {
+		return nil, fmt.Errorf("have token %v, want %s", p.nextTok.Type, token.SEMICOLON)
+	}
+	p.readToken()
+
+	return &stmt, nil
+}
+
+// ModuleExt is the extension of module files. Import paths can leave it out.
+const ModuleExt = ".mk"
+
+// ImportName returns the name a module is bound to by importing it from
+// importPath: the last element of the path, without the module extension.
+func ImportName(importPath string) string {
+	return strings.TrimSuffix(path.Base(importPath), ModuleExt)
+}
+
+func isIdentifier(s string) bool {
+	tok := lexer.New(s).NextToken()
+	return tok.Type == token.IDENT && tok.Literal == s
+}
+
+func (p *Parser) parseExportStatement() (*ast.ExportStatement, error) {
+	stmt := ast.ExportStatement{Token: p.currTok}
+	if p.blocks > 0 {
+		return nil, fmt.Errorf("have %s in a block, want it at top level", stmt.Token.Literal)
+	}
+
+	p.readToken()
+	if p.currTok.Type != token.LET && p.currTok.Type != token.CONST {
+		return nil, fmt.Errorf("have token type %s after %s, want %s", p.currTok.Type, stmt.Token.Literal, token.LET)
+	}
+
+	let, err := p.parseLetStatement()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Let = let
+
+	return &stmt, nil
+}
@@ -199,3 +199,570 @@ func TestString(t *testing.T) {
 		t.Fatalf("have program string %s, want %s", str, want)
 	}
 }
+
+func TestParseErrors(t *testing.T) {
+	input := `let = 5;
+return 1
+99999999999999999999;`
+	want := []string{
+		"1:1: have next token type =, want IDENT",
+		"2:1: have token INT, want ;",
+		"3:1: could not parse token: {INT 99999999999999999999 3:1} into int64: strconv.ParseInt: parsing \"99999999999999999999\": value out of range",
+	}
+
+	par := parser.New(lexer.New(input))
+	_, err := par.Parse()
+	errs, ok := err.(parser.ErrorList)
+	if !ok {
+		t.Fatalf("have error type %T, want %T", err, parser.ErrorList{})
+	}
+
+	if len(errs) != len(want) {
+		t.Fatalf("have %v errors %v, want %v", len(errs), errs, len(want))
+	}
+	for i, e := range errs {
+		if e.Error() != want[i] {
+			t.Fatalf("have error %s, want %s", e, want[i])
+		}
+	}
+}
+
+func TestParseErrorsInBlocks(t *testing.T) {
+	input := `while (true) { break; }
+while (x) {
+	let = 1;
+	if (x) { break; }
+}
+for (i in list) { let y = { }; continue; } x;`
+	want := []string{
+		"2:1: failed parsing while body: have next token type =, want IDENT",
+		"6:1: failed parsing for body: failed parsing expression in let statement: have token type {, want an expression",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	errs, ok := err.(parser.ErrorList)
+	if !ok {
+		t.Fatalf("have error type %T, want %T", err, parser.ErrorList{})
+	}
+
+	if len(errs) != len(want) {
+		t.Fatalf("have %v errors %v, want %v", len(errs), errs, len(want))
+	}
+	for i, e := range errs {
+		if e.Error() != want[i] {
+			t.Fatalf("have error %s, want %s", e, want[i])
+		}
+	}
+
+	// parsing goes on after the failed blocks
+	if str := prog.String(); str != "while (true) { break }\nx" {
+		t.Fatalf("have program %s, want the first loop, and x", str)
+	}
+}
+
+func TestTryStatement(t *testing.T) {
+	input := `
+		try { throw 1; } catch (err) { err; }
+		try { let foo = 1; } finally { foo; }
+		try { } catch (err) { } finally { }
+	`
+	want := []string{
+		"try { throw 1 } catch (err) { err }",
+		"try { let foo = 1; } finally { foo }",
+		"try {  } catch (err) {  } finally {  }",
+	}
+
+	par := parser.New(lexer.New(input))
+	prog, err := par.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prog.Statements) != len(want) {
+		t.Fatalf("have %v statements, want %v", len(prog.Statements), len(want))
+	}
+
+	for i, stmt := range prog.Statements {
+		tryStmt, ok := stmt.(*ast.TryStatement)
+		if !ok {
+			t.Fatalf("have statement type %T, want %T", stmt, &ast.TryStatement{})
+		}
+		if tryStmt.String() != want[i] {
+			t.Fatalf("have try statement %s, want %s", tryStmt.String(), want[i])
+		}
+	}
+
+	throwStmt, ok := prog.Statements[0].(*ast.TryStatement).Body.Statements[0].(*ast.ThrowStatement)
+	if !ok {
+		t.Fatalf("have statement type %T, want %T", prog.Statements[0].(*ast.TryStatement).Body.Statements[0], &ast.ThrowStatement{})
+	}
+	if throwStmt.Value.TokenLiteral() != "1" {
+		t.Fatalf("have thrown value %s, want %s", throwStmt.Value.TokenLiteral(), "1")
+	}
+}
+
+func TestTryStatementErrors(t *testing.T) {
+	inputs := map[string]string{
+		`try { 1; }`:             "1:1: have next token type EOF after try block, want CATCH or FINALLY",
+		`try { 1; } catch { 2 }`: "1:1: failed parsing catch parameter: have next token type {, want (",
+		`try { throw 1 }`:        "1:1: failed parsing try block: have token }, want ;",
+		`try { 1;`:               "1:1: failed parsing try block: have token type EOF in block, want }",
+	}
+
+	for input, want := range inputs {
+		_, err := parser.New(lexer.New(input)).Parse()
+		errs, ok := err.(parser.ErrorList)
+		if !ok || len(errs) == 0 {
+			t.Fatalf("have error %v parsing %s, want %s", err, input, want)
+		}
+		if errs[0].Error() != want {
+			t.Fatalf("have error %s parsing %s, want %s", errs[0], input, want)
+		}
+	}
+}
+
+func TestLoopStatement(t *testing.T) {
+	input := `
+		while (!done) { continue; }
+		for (item in items) { try { break; } finally { } }
+		while (1) { for (i in list) { } break; }
+	`
+	want := []string{
+		"while ((!) done) { continue }",
+		"for (item in items) { try { break } finally {  } }",
+		"while (1) { for (i in list) {  } break }",
+	}
+
+	par := parser.New(lexer.New(input))
+	prog, err := par.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prog.Statements) != len(want) {
+		t.Fatalf("have %v statements, want %v", len(prog.Statements), len(want))
+	}
+	for i, stmt := range prog.Statements {
+		if stmt.String() != want[i] {
+			t.Fatalf("have loop %s, want %s", stmt.String(), want[i])
+		}
+	}
+
+	forStmt, ok := prog.Statements[1].(*ast.ForStatement)
+	if !ok {
+		t.Fatalf("have statement type %T, want %T", prog.Statements[1], &ast.ForStatement{})
+	}
+	if forStmt.Variable.Value != "item" || forStmt.Iterable.TokenLiteral() != "items" {
+		t.Fatalf("have for (%s in %s), want for (item in items)", forStmt.Variable.Value, forStmt.Iterable.TokenLiteral())
+	}
+}
+
+func TestLoopStatementErrors(t *testing.T) {
+	inputs := map[string]string{
+		`break;`:                        "1:1: have break outside of a loop",
+		`while (1) { } continue;`:       "1:15: have continue outside of a loop",
+		`while 1 { }`:                   "1:1: failed parsing while condition: have next token type INT, want (",
+		`while () { }`:                  "1:1: failed parsing while condition: have token type ), want an expression",
+		`for (i list) { }`:              "1:1: failed parsing for variable: have next token type IDENT, want IN",
+		`for (i in list) { break }`:     "1:1: failed parsing for body: have token }, want ;",
+		`try { while (1) { } break; } `: "1:1: failed parsing try block: have break outside of a loop",
+	}
+
+	for input, want := range inputs {
+		_, err := parser.New(lexer.New(input)).Parse()
+		errs, ok := err.(parser.ErrorList)
+		if !ok || len(errs) == 0 {
+			t.Fatalf("have error %v parsing %s, want %s", err, input, want)
+		}
+		if errs[0].Error() != want {
+			t.Fatalf("have error %s parsing %s, want %s", errs[0], input, want)
+		}
+	}
+}
+
+func TestAssignExpression(t *testing.T) {
+	input := `foo = 5; foo += -bar; a = b *= c; const baz = 1;`
+	want := []string{"foo = 5", "foo += (-) bar", "a = b *= c", "const baz = 1;"}
+
+	par := parser.New(lexer.New(input))
+	prog, err := par.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prog.Statements) != len(want) {
+		t.Fatalf("have %v statements, want %v", len(prog.Statements), len(want))
+	}
+	for i, stmt := range prog.Statements {
+		if stmt.String() != want[i] {
+			t.Fatalf("have statement %s, want %s", stmt.String(), want[i])
+		}
+	}
+
+	stmt := prog.Statements[2].(*ast.ExpressionStatement)
+	assign, ok := stmt.Expression.(*ast.AssignExpression)
+	if !ok {
+		t.Fatalf("have expression type %T, want %T", stmt.Expression, &ast.AssignExpression{})
+	}
+	if _, ok := assign.Value.(*ast.AssignExpression); !ok {
+		t.Fatalf("have assigned value type %T, want %T", assign.Value, &ast.AssignExpression{})
+	}
+
+	if let := prog.Statements[3].(*ast.LetStatement); !let.Const() {
+		t.Fatalf("have %s not const, want const", let)
+	}
+}
+
+func TestModuleStatement(t *testing.T) {
+	input := `import "lib/strings"; import "util.mk"; export let foo = "bar"; export const baz = 1;`
+	want := []string{`import "lib/strings";`, `import "util.mk";`, `export let foo = "bar";`, `export const baz = 1;`}
+
+	par := parser.New(lexer.New(input))
+	prog, err := par.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prog.Statements) != len(want) {
+		t.Fatalf("have %v statements, want %v", len(prog.Statements), len(want))
+	}
+	for i, stmt := range prog.Statements {
+		if stmt.String() != want[i] {
+			t.Fatalf("have statement %s, want %s", stmt.String(), want[i])
+		}
+	}
+
+	for i, name := range []string{"strings", "util"} {
+		imp := prog.Statements[i].(*ast.ImportStatement)
+		if imp.Name.Value != name {
+			t.Fatalf("have import name %s, want %s", imp.Name.Value, name)
+		}
+	}
+}
+
+func TestModuleStatementErrors(t *testing.T) {
+	inputs := map[string]string{
+		`import foo;`:                           "1:1: failed parsing import path: have next token type IDENT, want STRING",
+		`import "lib/my-mod";`:                  `1:1: have import path "lib/my-mod", want it to end in a variable name`,
+		`import "lib/let";`:                     `1:1: have import path "lib/let", want it to end in a variable name`,
+		`import "lib.v2";`:                      `1:1: have import path "lib.v2", want it to end in a variable name`,
+		`export foo;`:                           "1:1: have token type IDENT after export, want LET",
+		`try { export let x = 1; } finally { }`: "1:1: failed parsing try block: have export in a block, want it at top level",
+	}
+
+	for input, want := range inputs {
+		_, err := parser.New(lexer.New(input)).Parse()
+		errs, ok := err.(parser.ErrorList)
+		if !ok || len(errs) == 0 {
+			t.Fatalf("have error %v parsing %s, want %s", err, input, want)
+		}
+		if errs[0].Error() != want {
+			t.Fatalf("have error %s parsing %s, want %s", errs[0], input, want)
+		}
+	}
+}
+
+func TestTypeAnnotation(t *testing.T) {
+	input := `let x: int = 5; const names: [string] = y; export let m: {string: [int]} = z; let f: fn(int, string) -> fn() -> bool = g; let plain = 1;
+let h = fn(a: int, b, c: {string: int}) -> [int] { a };`
+	want := []string{
+		`let x: int = 5;`,
+		`const names: [string] = y;`,
+		`export let m: {string: [int]} = z;`,
+		`let f: fn(int, string) -> fn() -> bool = g;`,
+		`let plain = 1;`,
+		`let h = fn(a: int, b, c: {string: int}) -> [int] { a };`,
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prog.Statements) != len(want) {
+		t.Fatalf("have %v statements, want %v", len(prog.Statements), len(want))
+	}
+	for i, stmt := range prog.Statements {
+		if stmt.String() != want[i] {
+			t.Fatalf("have statement %s, want %s", stmt.String(), want[i])
+		}
+	}
+
+	if typ := prog.Statements[4].(*ast.LetStatement).Type; typ != nil {
+		t.Fatalf("have type %s without an annotation, want none", typ)
+	}
+}
+
+func TestTypeAnnotationErrors(t *testing.T) {
+	inputs := map[string]string{
+		`let x: = 5;`:                     "1:1: failed parsing type of x: have token type =, want a type",
+		`let x: [int = 5;`:                "1:1: failed parsing type of x: have next token type =, want ]",
+		`let x: {string int} = 5;`:        "1:1: failed parsing type of x: have next token type IDENT, want :",
+		`let x: fn(int bool) -> int = 5;`: "1:1: failed parsing type of x: have token type IDENT in parameter types, want )",
+		`let x: fn(int) int = 5;`:         "1:1: failed parsing type of x: have next token type IDENT, want ->",
+		`fn(a: ) { a }`:                   "1:1: failed parsing parameter type: have token type ), want a type",
+		`fn(a: int b) { a }`:              "1:1: have token type IDENT in function parameters, want )",
+		`fn(a) -> { a }`:                  "1:1: failed parsing result type: have next token type }, want :",
+	}
+
+	for input, want := range inputs {
+		_, err := parser.New(lexer.New(input)).Parse()
+		errs, ok := err.(parser.ErrorList)
+		if !ok || len(errs) == 0 {
+			t.Fatalf("have error %v parsing %s, want %s", err, input, want)
+		}
+		if errs[0].Error() != want {
+			t.Fatalf("have error %s parsing %s, want %s", errs[0], input, want)
+		}
+	}
+}
+
+func TestTemplateLiteral(t *testing.T) {
+	input := `let msg = "Hello ${name}, you have ${-count} items"; "${"in ${x}"}";`
+	want := []string{
+		`let msg = "Hello ${name}, you have ${(-) count} items";`,
+		`"${"in ${x}"}"`,
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prog.Statements) != len(want) {
+		t.Fatalf("have %v statements, want %v", len(prog.Statements), len(want))
+	}
+	for i, stmt := range prog.Statements {
+		if stmt.String() != want[i] {
+			t.Fatalf("have statement %s, want %s", stmt.String(), want[i])
+		}
+	}
+
+	tmpl := prog.Statements[0].(*ast.LetStatement).Value.(*ast.TemplateLiteral)
+	if len(tmpl.Parts) != 3 || tmpl.Parts[2] != " items" || len(tmpl.Expressions) != 2 {
+		t.Fatalf("have parts %q, and expressions %v, want 3 parts, and 2 expressions", tmpl.Parts, tmpl.Expressions)
+	}
+}
+
+func TestTemplateLiteralErrors(t *testing.T) {
+	inputs := map[string]string{
+		`"a ${}";`:     "1:1: failed parsing interpolated expression: have token type TEMPLATE_TAIL, want an expression",
+		`"a ${x y}";`:  "1:1: have next token type IDENT in interpolation, want }",
+		`let s = "${x`: "1:1: failed parsing expression in let statement: have next token type EOF in interpolation, want }",
+	}
+
+	for input, want := range inputs {
+		_, err := parser.New(lexer.New(input)).Parse()
+		errs, ok := err.(parser.ErrorList)
+		if !ok || len(errs) == 0 {
+			t.Fatalf("have error %v parsing %s, want %s", err, input, want)
+		}
+		if errs[0].Error() != want {
+			t.Fatalf("have error %s parsing %s, want %s", errs[0], input, want)
+		}
+	}
+}
+
+func TestInfixExpression(t *testing.T) {
+	tests := map[string]string{
+		`1 + 2 * 3;`:                  "(1 + (2 * 3))",
+		`1 - 2 - 3;`:                  "((1 - 2) - 3)",
+		`-a * b;`:                     "((-) a * b)",
+		`!true == false;`:             "((!) true == false)",
+		`a + b < c == d > e;`:         "(((a + b) < c) == (d > e))",
+		`(1 + 2) * 3;`:                "((1 + 2) * 3)",
+		`x = a + b;`:                  "x = (a + b)",
+		`"a ${n + 1}";`:               `"a ${(n + 1)}"`,
+		`if (a < b) { a } else { b }`: "if (a < b) { a } else { b }",
+		`if (x) { 1; };`:              "if x { 1 }",
+	}
+
+	for input, want := range tests {
+		prog, err := parser.New(lexer.New(input)).Parse()
+		if err != nil {
+			t.Fatalf("failed parsing %s: %s", input, err)
+		}
+		if len(prog.Statements) != 1 {
+			t.Fatalf("have %v statements parsing %s, want 1", len(prog.Statements), input)
+		}
+		if have := prog.String(); have != want {
+			t.Fatalf("have %s parsing %s, want %s", have, input, want)
+		}
+	}
+}
+
+func TestInfixExpressionErrors(t *testing.T) {
+	inputs := map[string]string{
+		`1 +;`:             "1:1: failed parsing right operand of +: have token type ;, want an expression",
+		`(1 + 2;`:          "1:1: have next token type ;, want )",
+		`a + b = 1;`:       "1:7: have token type =, want an expression",
+		`if (x) 1;`:        "1:1: failed parsing if block: have next token type INT, want {",
+		`if x { }`:         "1:1: failed parsing if condition: have next token type IDENT, want (",
+		`if (x) { } else;`: "1:1: failed parsing else block: have next token type ;, want {",
+		`1 | 2;`:           `1:3: have illegal token "|"`,
+	}
+
+	for input, want := range inputs {
+		_, err := parser.New(lexer.New(input)).Parse()
+		errs, ok := err.(parser.ErrorList)
+		if !ok || len(errs) == 0 {
+			t.Fatalf("have error %v parsing %s, want %s", err, input, want)
+		}
+		if errs[0].Error() != want {
+			t.Fatalf("have error %s parsing %s, want %s", errs[0], input, want)
+		}
+	}
+}
+
+func TestCallExpression(t *testing.T) {
+	tests := map[string]string{
+		`add(1, 2 * 3);`:                "add(1, (2 * 3))",
+		`f()(x);`:                       "f()(x)",
+		`-f(x);`:                        "(-) f(x)",
+		`fn(x, y) { x + y }(1, 2);`:     "fn(x, y) { (x + y) }(1, 2)",
+		`let add = fn(a, b) { a + b };`: "let add = fn(a, b) { (a + b) };",
+		`x |> f(1);`:                    "f(x, 1)",
+		`xs |> map(g) |> sum();`:        "sum(map(xs, g))",
+		`a == b |> f();`:                "f((a == b))",
+		`f(_, 2);`:                      "fn(_1) { fn(_2) { _1(_2, 2) } }(f)",
+		`f(_, x, _);`:                   "fn(_1, _2) { fn(_3, _4) { _1(_3, _2, _4) } }(f, x)",
+		`g(a)(_, h(b));`:                "fn(_1, _2) { fn(_3) { _1(_3, _2) } }(g(a), h(b))",
+		`f(_, 1)(2);`:                   "fn(_1) { fn(_2) { _1(_2, 1) } }(f)(2)",
+		`x |> f(1, _);`:                 "f(1, x)",
+		`x |> (f(_, 1));`:               "f(x, 1)",
+		`x |> f(_, y, _);`:              "fn(_1, _2, _3) { fn(_4) { _1(_2, _3, _4) } }(f, x, y)",
+		`while (x) { fn() { 1 }; }`:     "while (x) { fn() { 1 } }",
+		`fn() { return 1; }`:            "fn() { return 1 }",
+	}
+
+	for input, want := range tests {
+		prog, err := parser.New(lexer.New(input)).Parse()
+		if err != nil {
+			t.Fatalf("failed parsing %s: %s", input, err)
+		}
+		if len(prog.Statements) != 1 {
+			t.Fatalf("have %v statements parsing %s, want 1", len(prog.Statements), input)
+		}
+		if have := prog.String(); have != want {
+			t.Fatalf("have %s parsing %s, want %s", have, input, want)
+		}
+	}
+}
+
+func TestCallExpressionErrors(t *testing.T) {
+	inputs := map[string]string{
+		`x |> f;`:                        "1:1: have f after |>, want a call",
+		`x |>;`:                          "1:1: failed parsing right operand of |>: have token type ;, want an expression",
+		`f(1;`:                           "1:1: have token type ; in call arguments, want )",
+		`_ + 1;`:                         "1:1: have _ outside of call arguments",
+		`f(_ + 1);`:                      "1:1: failed parsing call argument: have _ outside of call arguments",
+		`fn(1) { }`:                      `1:1: have token "1" in function parameters, want a name`,
+		`fn(a b) { }`:                    "1:1: have token type IDENT in function parameters, want )",
+		`fn(a) a;`:                       "1:1: failed parsing function body: have next token type IDENT, want {",
+		`while (x) { fn() { break; }; }`: "1:1: failed parsing while body: failed parsing function body: have break outside of a loop",
+	}
+
+	for input, want := range inputs {
+		_, err := parser.New(lexer.New(input)).Parse()
+		errs, ok := err.(parser.ErrorList)
+		if !ok || len(errs) == 0 {
+			t.Fatalf("have error %v parsing %s, want %s", err, input, want)
+		}
+		if errs[0].Error() != want {
+			t.Fatalf("have error %s parsing %s, want %s", errs[0], input, want)
+		}
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	tests := map[string]string{
+		`spawn fn() { work(); };`:                         "spawn fn() { work() }",
+		`let t = spawn f(1, x);`:                          "let t = spawn f(1, x);",
+		`spawn fn(a) { a }(1);`:                           "spawn fn(a) { a }(1)",
+		`select { }`:                                      "select {  }",
+		`select { case v = recv(ch) { v; } }`:             "select { case v = recv(ch) { v } }",
+		`select { case send(out, 1) { } default { x; } }`: "select { case send(out, 1) {  } default { x } }",
+	}
+
+	for input, want := range tests {
+		prog, err := parser.New(lexer.New(input)).Parse()
+		if err != nil {
+			t.Fatalf("failed parsing %s: %s", input, err)
+		}
+		if len(prog.Statements) != 1 {
+			t.Fatalf("have %v statements parsing %s, want 1", len(prog.Statements), input)
+		}
+		if have := prog.String(); have != want {
+			t.Fatalf("have %s parsing %s, want %s", have, input, want)
+		}
+	}
+}
+
+func TestConcurrencyErrors(t *testing.T) {
+	inputs := map[string]string{
+		`spawn f;`:                           "1:1: have f after spawn, want a call, or function literal",
+		`spawn;`:                             "1:1: failed parsing spawned call: have token type ;, want an expression",
+		`select x`:                           "1:1: failed parsing select: have next token type IDENT, want {",
+		`select { x; }`:                      "1:1: have token type IDENT in select, want CASE or DEFAULT",
+		`select { case ch { } }`:             "1:1: have ch in select case, want a channel operation call",
+		`select { case recv(ch) x }`:         "1:1: failed parsing select case: have next token type IDENT, want {",
+		`select { default { } default { } }`: "1:1: have a second default in select",
+		`select { case v = recv(ch) { v; }`:  "1:1: have token type EOF in select, want CASE or DEFAULT",
+	}
+
+	for input, want := range inputs {
+		_, err := parser.New(lexer.New(input)).Parse()
+		errs, ok := err.(parser.ErrorList)
+		if !ok || len(errs) == 0 {
+			t.Fatalf("have error %v parsing %s, want %s", err, input, want)
+		}
+		if errs[0].Error() != want {
+			t.Fatalf("have error %s parsing %s, want %s", errs[0], input, want)
+		}
+	}
+}
+
+func TestMacros(t *testing.T) {
+	tests := map[string]string{
+		`quote(a + b);`: "quote((a + b))",
+		`let unless = macro(cond, yes, no) { quote(if (!(unquote(cond))) { unquote(yes) } else { unquote(no) }) };`: "let unless = macro(cond, yes, no) { quote(if (!) unquote(cond) { unquote(yes) } else { unquote(no) }) };",
+		`quote(unquote(quote(unquote(x))));`: "quote(unquote(quote(unquote(x))))",
+		`unless(x > 1, a, b);`:               "unless((x > 1), a, b)",
+	}
+
+	for input, want := range tests {
+		prog, err := parser.New(lexer.New(input)).Parse()
+		if err != nil {
+			t.Fatalf("failed parsing %s: %s", input, err)
+		}
+		if len(prog.Statements) != 1 {
+			t.Fatalf("have %v statements parsing %s, want 1", len(prog.Statements), input)
+		}
+		if have := prog.String(); have != want {
+			t.Fatalf("have %s parsing %s, want %s", have, input, want)
+		}
+	}
+}
+
+func TestMacroErrors(t *testing.T) {
+	inputs := map[string]string{
+		`unquote(x);`:                       "1:1: have unquote outside of a quote",
+		`quote(unquote(unquote(x)));`:       "1:1: failed parsing quote: failed parsing unquote: have unquote outside of a quote",
+		`quote x;`:                          "1:1: failed parsing quote: have next token type IDENT, want (",
+		`quote(x;`:                          "1:1: failed parsing quote: have next token type ;, want )",
+		`macro(1) { }`:                      `1:1: have token "1" in macro parameters, want a name`,
+		`macro(a) a;`:                       "1:1: failed parsing macro body: have next token type IDENT, want {",
+		`while (x) { macro() { break; }; }`: "1:1: failed parsing while body: failed parsing macro body: have break outside of a loop",
+	}
+
+	for input, want := range inputs {
+		_, err := parser.New(lexer.New(input)).Parse()
+		errs, ok := err.(parser.ErrorList)
+		if !ok || len(errs) == 0 {
+			t.Fatalf("have error %v parsing %s, want %s", err, input, want)
+		}
+		if errs[0].Error() != want {
+			t.Fatalf("have error %s parsing %s, want %s", errs[0], input, want)
+		}
+	}
+}
@@ -4,15 +4,16 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"log"
 	"monkey/lexer"
-	"monkey/token"
+	"monkey/optimizer"
+	"monkey/parser"
 )
 
 const prompt = ">> "
 
-// Start reapetedly scans in, and prints tokens in its text.
-func Start(in io.Reader, out io.Writer) {
+// Start reapetedly scans in, and prints the program parsed from its text.
+// Constant expressions are folded unless optimize is false.
+func Start(in io.Reader, out io.Writer, optimize bool) {
 	scanner := bufio.NewScanner(in)
 	for {
 		fmt.Print(prompt)
@@ -21,10 +22,15 @@ func Start(in io.Reader, out io.Writer) {
 		}
 
 		txt := scanner.Text()
-		l := lexer.New(txt)
+		prog, err := parser.New(lexer.New(txt)).Parse()
+		if err != nil {
+			fmt.Fprintln(out, err)
+			continue
+		}
 
-		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
-			log.Printf("%+#v\n", tok)
+		if optimize {
+			optimizer.Optimize(prog)
 		}
+		fmt.Fprintln(out, prog.String())
 	}
 }
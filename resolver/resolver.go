@@ -0,0 +1,277 @@
+package resolver
+
+import (
+	"fmt"
+	"monkey/ast"
+	"monkey/token"
+	"sort"
+)
+
+// Kind is the kind of problem a Diagnostic reports.
+type Kind string
+
+const (
+	// Undefined is a variable used, or assigned without being bound
+	Undefined Kind = "undefined"
+	// Unused is a variable bound, but never used
+	Unused Kind = "unused"
+	// Shadowed is a variable bound again, hiding its previous binding
+	Shadowed Kind = "shadowed"
+	// ConstAssigned is a const variable being reassigned
+	ConstAssigned Kind = "const-assigned"
+)
+
+// Diagnostic is a problem found while resolving a program.
+type Diagnostic struct {
+	Kind
+	Pos token.Position
+	Msg string
+}
+
+// String returns the diagnostic's position, and message
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Msg)
+}
+
+type binding struct {
+	ident    *ast.Identifier
+	used     bool
+	constant bool
+}
+
+type scope struct {
+	outer    *scope
+	names    map[string]int // name to slot of its latest binding
+	bindings []*binding
+}
+
+// Declarations maps each variable use to the name in the let statement that
+// bound it.
+type Declarations map[*ast.Identifier]*ast.Identifier
+
+type resolver struct {
+	scope *scope
+	diags []Diagnostic
+	decls Declarations
+}
+
+// Resolve binds every identifier in prog to the let statement that declared
+// it, setting the identifier's Depth and Slot. It returns problems found
+// along the way, ordered by position.
+func Resolve(prog *ast.Program) []Diagnostic {
+	diags, _ := ResolveDeclarations(prog)
+	return diags
+}
+
+// ResolveDeclarations is Resolve, also returning where each variable use was
+// declared.
+func ResolveDeclarations(prog *ast.Program) ([]Diagnostic, Declarations) {
+	r := &resolver{decls: Declarations{}}
+	r.beginScope()
+	for _, stmt := range prog.Statements {
+		r.resolveStatement(stmt)
+	}
+	r.endScope()
+
+	sort.SliceStable(r.diags, func(i, j int) bool {
+		a, b := r.diags[i].Pos, r.diags[j].Pos
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return r.diags, r.decls
+}
+
+func (r *resolver) beginScope() {
+	r.scope = &scope{outer: r.scope, names: map[string]int{}}
+}
+
+// endScope reports the scope's unused bindings, and returns to its outer scope.
+func (r *resolver) endScope() {
+	for _, b := range r.scope.bindings {
+		if !b.used {
+			r.report(Unused, b.ident.Token.Pos, "%s declared but not used", b.ident.Value)
+		}
+	}
+	r.scope = r.scope.outer
+}
+
+func (r *resolver) report(kind Kind, pos token.Position, format string, args ...interface{}) {
+	r.diags = append(r.diags, Diagnostic{Kind: kind, Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+// declare binds ident in the current scope.
+func (r *resolver) declare(ident *ast.Identifier, constant bool) {
+	for s := r.scope; s != nil; s = s.outer {
+		if slot, ok := s.names[ident.Value]; ok {
+			prev := s.bindings[slot].ident
+			r.report(Shadowed, ident.Token.Pos, "%s shadows declaration at %s", ident.Value, prev.Token.Pos)
+			break
+		}
+	}
+
+	ident.Depth = 0
+	ident.Slot = len(r.scope.bindings)
+	r.scope.names[ident.Value] = ident.Slot
+	r.scope.bindings = append(r.scope.bindings, &binding{ident: ident, constant: constant})
+}
+
+// lookup binds ident to the closest declaration of its name, returning nil
+// if there is none.
+func (r *resolver) lookup(ident *ast.Identifier) *binding {
+	depth := 0
+	for s := r.scope; s != nil; s = s.outer {
+		if slot, ok := s.names[ident.Value]; ok {
+			r.decls[ident] = s.bindings[slot].ident
+			ident.Depth = depth
+			ident.Slot = slot
+			return s.bindings[slot]
+		}
+		depth++
+	}
+
+	ident.Depth = -1
+	return nil
+}
+
+// use binds ident to the closest declaration of its name, and marks it used.
+func (r *resolver) use(ident *ast.Identifier) {
+	b := r.lookup(ident)
+	if b == nil {
+		r.report(Undefined, ident.Token.Pos, "undefined: %s", ident.Value)
+		return
+	}
+	b.used = true
+}
+
+// assign binds the assigned variable to its declaration. Only compound
+// assignments, ex: foo += 1, use the variable's value.
+func (r *resolver) assign(expr *ast.AssignExpression) {
+	b := r.lookup(expr.Name)
+	if b == nil {
+		r.report(Undefined, expr.Name.Token.Pos, "assignment to undeclared %s", expr.Name.Value)
+		return
+	}
+	if b.constant {
+		r.report(ConstAssigned, expr.Name.Token.Pos, "cannot assign to %s, declared const at %s", expr.Name.Value, b.ident.Token.Pos)
+	}
+	if expr.Token.Type != token.ASSIGN {
+		b.used = true
+	}
+}
+
+func (r *resolver) resolveStatement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		if _, ok := stmt.Value.(*ast.FunctionLiteral); ok {
+			// functions can call themselves, but that doesn't use them
+			r.declare(stmt.Name, stmt.Const())
+			r.resolveExpression(stmt.Value)
+			r.scope.bindings[stmt.Name.Slot].used = false
+			return
+		}
+		// other values can't refer to the variable they're being bound to
+		r.resolveExpression(stmt.Value)
+		r.declare(stmt.Name, stmt.Const())
+	case *ast.ImportStatement:
+		// modules can't be reassigned
+		r.declare(stmt.Name, true)
+	case *ast.ExportStatement:
+		r.resolveStatement(stmt.Let)
+		// exports are used by the modules importing them
+		r.scope.bindings[stmt.Let.Name.Slot].used = true
+	case *ast.ReturnStatement:
+		r.resolveExpression(stmt.Value)
+	case *ast.ThrowStatement:
+		r.resolveExpression(stmt.Value)
+	case *ast.ExpressionStatement:
+		r.resolveExpression(stmt.Expression)
+	case *ast.BlockStatement:
+		r.resolveBlock(stmt)
+	case *ast.WhileStatement:
+		r.resolveExpression(stmt.Condition)
+		r.resolveBlock(stmt.Body)
+	case *ast.ForStatement:
+		r.resolveExpression(stmt.Iterable)
+		r.resolveBlock(stmt.Body, stmt.Variable)
+	case *ast.TryStatement:
+		r.resolveBlock(stmt.Body)
+		if stmt.Catch != nil {
+			r.resolveBlock(stmt.Catch, stmt.Param)
+		}
+		if stmt.Finally != nil {
+			r.resolveBlock(stmt.Finally)
+		}
+	case *ast.SelectStatement:
+		for _, c := range stmt.Cases {
+			r.resolveExpression(c.Operation)
+			if c.Name != nil {
+				r.resolveBlock(c.Body, c.Name)
+			} else {
+				r.resolveBlock(c.Body)
+			}
+		}
+		if stmt.Default != nil {
+			r.resolveBlock(stmt.Default)
+		}
+	}
+}
+
+// resolveBlock resolves block in a new scope, with params bound in it.
+func (r *resolver) resolveBlock(block *ast.BlockStatement, params ...*ast.Identifier) {
+	r.beginScope()
+	for _, param := range params {
+		r.declare(param, false)
+		// parameters are often unused on purpose, ex: catch (e) { },
+		// for (i in list) { }, or fn(a, b) { b }
+		r.scope.bindings[param.Slot].used = true
+	}
+	for _, stmt := range block.Statements {
+		r.resolveStatement(stmt)
+	}
+	r.endScope()
+}
+
+func (r *resolver) resolveExpression(expr ast.Expression) {
+	switch expr := expr.(type) {
+	case *ast.Identifier:
+		r.use(expr)
+	case *ast.PrefixExpression:
+		r.resolveExpression(expr.Expression)
+	case *ast.InfixExpression:
+		r.resolveExpression(expr.Left)
+		r.resolveExpression(expr.Right)
+	case *ast.IfExpression:
+		r.resolveExpression(expr.Condition)
+		r.resolveBlock(expr.Consequence)
+		if expr.Alternative != nil {
+			r.resolveBlock(expr.Alternative)
+		}
+	case *ast.FunctionLiteral:
+		r.resolveBlock(expr.Body, expr.Parameters...)
+	case *ast.CallExpression:
+		r.resolveExpression(expr.Function)
+		for _, arg := range expr.Arguments {
+			r.resolveExpression(arg)
+		}
+	case *ast.SpawnExpression:
+		r.resolveExpression(expr.Call)
+	case *ast.MacroLiteral:
+		r.resolveBlock(expr.Body, expr.Parameters...)
+	case *ast.QuoteExpression:
+		// quoted code is resolved where a macro expands it, but what it
+		// unquotes is evaluated here
+		ast.Inspect(expr.Node, func(node ast.Node) bool {
+			if unquote, ok := node.(*ast.UnquoteExpression); ok {
+				r.resolveExpression(unquote.Expression)
+				return false
+			}
+			return true
+		})
+	case *ast.TemplateLiteral:
+		for _, e := range expr.Expressions {
+			r.resolveExpression(e)
+		}
+	case *ast.AssignExpression:
+		r.resolveExpression(expr.Value)
+		r.assign(expr)
+	}
+}
@@ -0,0 +1,335 @@
+package resolver_test
+
+import (
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/parser"
+	"monkey/resolver"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	input := `
+let one = 1;
+let two = -one;
+let one = two;
+let three = four;
+return !one;
+`
+	want := []string{
+		"4:5: one shadows declaration at 2:5",
+		"5:5: three declared but not used",
+		"5:13: undefined: four",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := resolver.Resolve(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+}
+
+func TestResolveSlots(t *testing.T) {
+	input := `let one = 1; let two = one; let one = two; one; four;`
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver.Resolve(prog)
+
+	want := []struct {
+		depth, slot int
+	}{
+		{0, 0}, // one, in let two = one
+		{0, 1}, // two, in let one = two
+		{0, 2}, // one, after it was shadowed
+		{-1, 0},
+	}
+	idents := []*ast.Identifier{
+		prog.Statements[1].(*ast.LetStatement).Value.(*ast.Identifier),
+		prog.Statements[2].(*ast.LetStatement).Value.(*ast.Identifier),
+		prog.Statements[3].(*ast.ExpressionStatement).Expression.(*ast.Identifier),
+		prog.Statements[4].(*ast.ExpressionStatement).Expression.(*ast.Identifier),
+	}
+
+	for i, ident := range idents {
+		if ident.Depth != want[i].depth || ident.Slot != want[i].slot {
+			t.Fatalf("have %s depth %v slot %v, want depth %v slot %v", ident.Value, ident.Depth, ident.Slot, want[i].depth, want[i].slot)
+		}
+	}
+}
+
+func TestResolveBlocks(t *testing.T) {
+	input := `let one = 1;
+try { let one = one; throw one; } catch (err) { err; two; } finally { err; }`
+	want := []string{
+		"2:11: one shadows declaration at 1:5",
+		"2:54: undefined: two",
+		"2:71: undefined: err",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := resolver.Resolve(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+
+	body := prog.Statements[1].(*ast.TryStatement).Body
+	thrown := body.Statements[1].(*ast.ThrowStatement).Value.(*ast.Identifier)
+	if thrown.Depth != 0 || thrown.Slot != 0 {
+		t.Fatalf("have %s depth %v slot %v, want depth 0 slot 0", thrown.Value, thrown.Depth, thrown.Slot)
+	}
+	outer := body.Statements[0].(*ast.LetStatement).Value.(*ast.Identifier)
+	if outer.Depth != 1 || outer.Slot != 0 {
+		t.Fatalf("have %s depth %v slot %v, want depth 1 slot 0", outer.Value, outer.Depth, outer.Slot)
+	}
+}
+
+func TestResolveLoops(t *testing.T) {
+	input := `let list = 1; for (i in list) { let n = i; while (n) { n; } } i;`
+	want := []string{"1:63: undefined: i"}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := resolver.Resolve(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+}
+
+func TestResolveAssignments(t *testing.T) {
+	input := `let one = 1; const two = 2; let three = 3;
+one = two; two += 1; four = 4; three += 1;`
+	want := []string{
+		"1:5: one declared but not used",
+		"2:12: cannot assign to two, declared const at 1:20",
+		"2:22: assignment to undeclared four",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := resolver.Resolve(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+}
+
+func TestResolveModules(t *testing.T) {
+	input := `import "lib/util"; import "lib/unused"; export let one = 1; util = util;`
+	want := []string{
+		`1:27: unused declared but not used`,
+		`1:61: cannot assign to util, declared const at 1:8`,
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := resolver.Resolve(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+}
+
+func TestResolveTemplates(t *testing.T) {
+	input := `let name = "monkey"; let msg = "Hello ${name}, ${"from ${place}"}"; msg;`
+	want := []string{
+		"1:58: undefined: place",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := resolver.Resolve(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+}
+
+func TestResolveIfExpressions(t *testing.T) {
+	input := `let a = 1; let b = 2;
+if (a < b) { let c = a; } else { c + missing; }`
+	want := []string{
+		"2:18: c declared but not used",
+		"2:34: undefined: c",
+		"2:38: undefined: missing",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := resolver.Resolve(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+}
+
+func TestResolveFunctions(t *testing.T) {
+	input := `let k = 1;
+let add = fn(a, b) { let c = a; a + k };
+let inc = add(_, 1);
+inc(2) |> add(k);
+a;`
+	want := []string{
+		"2:26: c declared but not used",
+		"5:1: undefined: a",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := resolver.Resolve(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+}
+
+func TestResolveRecursion(t *testing.T) {
+	input := `let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } };
+fib(10);
+let loop = fn() { loop() };
+let x = x + 1;`
+	want := []string{
+		"3:5: loop declared but not used",
+		"4:5: x declared but not used",
+		"4:9: undefined: x",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags, decls := resolver.ResolveDeclarations(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+
+	let := prog.Statements[0].(*ast.LetStatement)
+	uses := 0
+	for _, decl := range decls {
+		if decl == let.Name {
+			uses++
+		}
+	}
+	if uses != 3 {
+		t.Fatalf("have %v uses of fib, want 3", uses)
+	}
+}
+
+func TestResolveConcurrency(t *testing.T) {
+	input := `let ch = 1; let work = fn(n) { n };
+spawn work(ch);
+select { case v = recv(ch) { v; } case send(ch, v) { } default { missing; } }`
+	want := []string{
+		"3:19: undefined: recv",
+		"3:40: undefined: send",
+		"3:49: undefined: v",
+		"3:66: undefined: missing",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := resolver.Resolve(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+}
+
+func TestResolveMacros(t *testing.T) {
+	input := `let twice = macro(x, unused) { quote(unquote(x) + unquote(x) + later + unquote(missing)) };
+twice(1, 2);`
+	want := []string{
+		"1:80: undefined: missing",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := resolver.Resolve(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+}
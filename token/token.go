@@ -1,5 +1,7 @@
 package token
 
+import "fmt"
+
 // Type is a token's type.
 type Type string
 
@@ -7,6 +9,18 @@ type Type string
 type Token struct {
 	Type
 	Literal string
+	Pos     Position
+}
+
+// Position is where a token starts in the input. Lines and columns start at 1.
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// String returns the position as line:column
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
 }
 
 const (
@@ -14,15 +28,30 @@ const (
 	ILLEGAL Type = "ILLEGAL"
 	// EOF is end of file
 	EOF = "EOF"
+	// COMMENT is a line comment, skipped by the lexer
+	COMMENT = "COMMENT"
 	// IDENT is a variable name
 	IDENT     = "IDENT"
 	INT       = "INT"
+	STRING    = "STRING"
 	COMMA     = ","
 	SEMICOLON = ";"
+	COLON     = ":"
 	LPAREN    = "("
 	RPAREN    = ")"
 	LBRACE    = "{"
 	RBRACE    = "}"
+	LBRACKET  = "["
+	RBRACKET  = "]"
+	// ARROW comes before a function type's result: fn(int) -> bool
+	ARROW = "->"
+
+	// A string with interpolations, "a ${x} b ${y} c", is lexed as
+	// TEMPLATE_HEAD "a ", x's tokens, TEMPLATE_MIDDLE " b ", y's tokens, and
+	// TEMPLATE_TAIL " c"
+	TEMPLATE_HEAD   = "TEMPLATE_HEAD"
+	TEMPLATE_MIDDLE = "TEMPLATE_MIDDLE"
+	TEMPLATE_TAIL   = "TEMPLATE_TAIL"
 
 	// Operators
 	ASSIGN   = "="
@@ -34,28 +63,76 @@ const (
 	LT       = "<"
 	GT       = ">"
 
+	// Compound assignment
+	PLUS_ASSIGN     = "+="
+	MINUS_ASSIGN    = "-="
+	ASTERISK_ASSIGN = "*="
+	SLASH_ASSIGN    = "/="
+
 	// Equality
 	EQ     = "=="
 	NOT_EQ = "!="
 
+	// PIPE passes its left operand as the first argument of the call on its
+	// right: x |> f(a) is f(x, a)
+	PIPE = "|>"
+
 	// Keywords
 	FUNCTION = "FUNCTION"
 	LET      = "LET"
+	CONST    = "CONST"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
+	THROW    = "THROW"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	WHILE    = "WHILE"
+	FOR      = "FOR"
+	IN       = "IN"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+	IMPORT   = "IMPORT"
+	EXPORT   = "EXPORT"
+	SPAWN    = "SPAWN"
+	SELECT   = "SELECT"
+	CASE     = "CASE"
+	DEFAULT  = "DEFAULT"
+	MACRO    = "MACRO"
+	QUOTE    = "QUOTE"
+	UNQUOTE  = "UNQUOTE"
 )
 
 var keywordType = map[string]Type{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"fn":       FUNCTION,
+	"let":      LET,
+	"const":    CONST,
+	"true":     TRUE,
+	"false":    FALSE,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"throw":    THROW,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
+	"while":    WHILE,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"import":   IMPORT,
+	"export":   EXPORT,
+	"spawn":    SPAWN,
+	"select":   SELECT,
+	"case":     CASE,
+	"default":  DEFAULT,
+	"macro":    MACRO,
+	"quote":    QUOTE,
+	"unquote":  UNQUOTE,
 }
 
 // IdentType returns a keyword token type, or IDENT
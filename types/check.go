@@ -0,0 +1,632 @@
+package types
+
+import (
+	"fmt"
+	"monkey/ast"
+	"monkey/resolver"
+	"monkey/token"
+)
+
+// Diagnostic is a type error.
+type Diagnostic struct {
+	Pos token.Position
+	Msg string
+}
+
+// String returns the diagnostic's position, and message
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Msg)
+}
+
+// Types maps the names bound by let statements, imports, and parameters to
+// their types.
+type Types map[*ast.Identifier]Type
+
+// scheme is a type, generic in vars: a let bound variable can be used as a
+// different instance of vars each time.
+type scheme struct {
+	vars []*Var
+	typ  Type
+}
+
+// generic is true if s is generic in v
+func (s *scheme) generic(v *Var) bool {
+	for _, sv := range s.vars {
+		if sv == v {
+			return true
+		}
+	}
+	return false
+}
+
+type checker struct {
+	decls    resolver.Declarations
+	env      map[*ast.Identifier]*scheme // by the name that declared it
+	declared []*ast.Identifier           // the names in env, in the order they were declared
+	assigned map[*ast.Identifier]bool    // names that are reassigned
+	result   Type                        // of the function being checked, nil outside of one
+	types    Types
+	nextID   int
+	diags    []Diagnostic
+}
+
+// Check infers the type of every variable in prog with Hindley-Milner type
+// inference, and returns the inferred types, and type errors. Undefined
+// variables aren't reported, the resolver does that.
+func Check(prog *ast.Program) (Types, []Diagnostic) {
+	_, decls := resolver.ResolveDeclarations(prog)
+	c := &checker{decls: decls, env: map[*ast.Identifier]*scheme{}, assigned: map[*ast.Identifier]bool{}, types: Types{}}
+	ast.Inspect(prog, func(node ast.Node) bool {
+		if assign, ok := node.(*ast.AssignExpression); ok {
+			if decl, ok := decls[assign.Name]; ok {
+				c.assigned[decl] = true
+			}
+		}
+		return true
+	})
+
+	for _, stmt := range prog.Statements {
+		c.checkStatement(stmt)
+	}
+	return c.types, c.diags
+}
+
+func (c *checker) report(pos token.Position, format string, args ...interface{}) {
+	c.diags = append(c.diags, Diagnostic{Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) fresh() *Var {
+	c.nextID++
+	return &Var{id: c.nextID}
+}
+
+// bind declares ident with type t, the same for every use of it
+func (c *checker) bind(ident *ast.Identifier, t Type) {
+	c.declare(ident, &scheme{typ: t})
+}
+
+func (c *checker) declare(ident *ast.Identifier, s *scheme) {
+	c.env[ident] = s
+	c.declared = append(c.declared, ident)
+	c.types[ident] = s.typ
+}
+
+// leave removes the names declared since declared had length mark from env,
+// as their scope ends. Their type variables would otherwise stay bound, and
+// keep later let bindings from being generalized.
+func (c *checker) leave(mark int) {
+	for _, ident := range c.declared[mark:] {
+		delete(c.env, ident)
+	}
+	c.declared = c.declared[:mark]
+}
+
+func (c *checker) checkBlock(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+	for _, stmt := range block.Statements {
+		c.checkStatement(stmt)
+	}
+}
+
+func (c *checker) checkStatement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		var t Type
+		if fn, ok := stmt.Value.(*ast.FunctionLiteral); ok {
+			// functions can call themselves, with the single type they
+			// have while being checked
+			self := c.fresh()
+			mark := len(c.declared)
+			c.bind(stmt.Name, self)
+			t = c.inferFunction(fn, self)
+			c.leave(mark)
+		} else {
+			t = c.value(stmt, stmt.Value)
+		}
+		if stmt.Type != nil {
+			want := c.annotation(stmt.Type)
+			if err := unify(want, t); err != nil {
+				c.report(ast.Pos(stmt.Value), "cannot use %s of type %s as %s", stmt.Value, t, want)
+			}
+			t = want
+		}
+		if c.assigned[stmt.Name] {
+			// variables that are reassigned must keep a single type, or
+			// an assignment could change it for every use
+			c.bind(stmt.Name, t)
+		} else {
+			c.declare(stmt.Name, c.generalize(t))
+		}
+	case *ast.ExportStatement:
+		c.checkStatement(stmt.Let)
+	case *ast.ImportStatement:
+		c.bind(stmt.Name, Module)
+	case *ast.ReturnStatement:
+		t := c.value(stmt, stmt.Value)
+		if c.result != nil {
+			c.returns(stmt.Value, t)
+		}
+	case *ast.ThrowStatement:
+		c.value(stmt, stmt.Value)
+	case *ast.ExpressionStatement:
+		if ifExp, ok := stmt.Expression.(*ast.IfExpression); ok {
+			// the value of an if statement isn't used, so its branches
+			// can have different types
+			c.inferIf(ifExp, false)
+			return
+		}
+		c.value(stmt, stmt.Expression)
+	case *ast.BlockStatement:
+		c.checkBlock(stmt)
+	case *ast.WhileStatement:
+		// any value can be a condition, by its truthiness
+		c.infer(stmt.Condition)
+		c.checkBlock(stmt.Body)
+	case *ast.ForStatement:
+		elem := c.fresh()
+		iter := c.infer(stmt.Iterable)
+		if err := unify(iter, &Array{Elem: elem}); err != nil {
+			c.report(ast.Pos(stmt.Iterable), "cannot iterate over %s", iter)
+		}
+		c.bind(stmt.Variable, elem)
+		c.checkBlock(stmt.Body)
+	case *ast.TryStatement:
+		c.checkBlock(stmt.Body)
+		if stmt.Catch != nil {
+			// anything can be thrown
+			c.bind(stmt.Param, c.fresh())
+			c.checkBlock(stmt.Catch)
+		}
+		c.checkBlock(stmt.Finally)
+	case *ast.SelectStatement:
+		for _, cs := range stmt.Cases {
+			// channels aren't typed yet, so neither are their values
+			c.infer(cs.Operation)
+			if cs.Name != nil {
+				c.bind(cs.Name, c.fresh())
+			}
+			c.checkBlock(cs.Body)
+		}
+		c.checkBlock(stmt.Default)
+	}
+}
+
+// annotation returns the type an annotation names. Unknown names are
+// reported, and left to be inferred.
+func (c *checker) annotation(typ ast.TypeExpression) Type {
+	switch typ := typ.(type) {
+	case *ast.NamedType:
+		switch Basic(typ.Name) {
+		case Int, Bool, String:
+			return Basic(typ.Name)
+		}
+		c.report(ast.Pos(typ), "unknown type %s", typ.Name)
+	case *ast.ArrayType:
+		return &Array{Elem: c.annotation(typ.Elem)}
+	case *ast.HashType:
+		return &Hash{Key: c.annotation(typ.Key), Value: c.annotation(typ.Value)}
+	case *ast.FuncType:
+		f := &Func{}
+		for _, p := range typ.Params {
+			f.Params = append(f.Params, c.annotation(p))
+		}
+		f.Result = c.annotation(typ.Result)
+		return f
+	}
+	return c.fresh()
+}
+
+// value returns the type of stmt's value, reporting it if there is none.
+func (c *checker) value(stmt ast.Statement, expr ast.Expression) Type {
+	if expr == nil {
+		c.report(ast.Pos(stmt), "missing value in %s statement", stmt.TokenLiteral())
+		return c.fresh()
+	}
+	return c.infer(expr)
+}
+
+// blockValue checks block, and returns the type of its value, and the
+// expression it is the value of: its last statement, if that is an
+// expression. Blocks without one have an unknown value, and no expression.
+func (c *checker) blockValue(block *ast.BlockStatement) (ast.Expression, Type) {
+	n := len(block.Statements)
+	if n == 0 {
+		return nil, c.fresh()
+	}
+	for _, stmt := range block.Statements[:n-1] {
+		c.checkStatement(stmt)
+	}
+
+	last, ok := block.Statements[n-1].(*ast.ExpressionStatement)
+	if !ok {
+		c.checkStatement(block.Statements[n-1])
+		return nil, c.fresh()
+	}
+	return last.Expression, c.value(last, last.Expression)
+}
+
+// returns reports if expr, of type t, can't be returned from the function
+// being checked.
+func (c *checker) returns(expr ast.Expression, t Type) {
+	if err := unify(c.result, t); err != nil {
+		c.report(ast.Pos(expr), "cannot return %s of type %s as %s", expr, t, c.result)
+	}
+}
+
+// infer returns the type of expr, reporting type errors in it.
+func (c *checker) infer(expr ast.Expression) Type {
+	switch expr := expr.(type) {
+	case *ast.Integer:
+		return Int
+	case *ast.Boolean:
+		return Bool
+	case *ast.StringLiteral:
+		return String
+	case *ast.TemplateLiteral:
+		// any value can be interpolated, by its string representation
+		for _, e := range expr.Expressions {
+			c.infer(e)
+		}
+		return String
+	case *ast.Identifier:
+		if s, ok := c.env[c.decls[expr]]; ok {
+			return c.instantiate(s)
+		}
+		return c.fresh()
+	case *ast.PrefixExpression:
+		t := c.infer(expr.Expression)
+		if expr.Operator == "!" {
+			// any value can be negated, by its truthiness
+			return Bool
+		}
+		if err := unify(t, Int); err != nil {
+			c.report(ast.Pos(expr), "cannot use %s%s of type %s as %s", expr.Operator, expr.Expression, t, Int)
+		}
+		return Int
+	case *ast.InfixExpression:
+		return c.inferInfix(expr)
+	case *ast.IfExpression:
+		return c.inferIf(expr, true)
+	case *ast.FunctionLiteral:
+		return c.inferFunction(expr, nil)
+	case *ast.CallExpression:
+		return c.inferCall(expr)
+	case *ast.SpawnExpression:
+		c.infer(expr.Call)
+		// what spawn returns is up to the runtime
+		return c.fresh()
+	case *ast.MacroLiteral:
+		c.checkMacro(expr)
+		return c.fresh()
+	case *ast.QuoteExpression:
+		// quoted code is checked where a macro expands it, but what it
+		// unquotes is evaluated here
+		ast.Inspect(expr.Node, func(node ast.Node) bool {
+			if unquote, ok := node.(*ast.UnquoteExpression); ok {
+				c.infer(unquote.Expression)
+				return false
+			}
+			return true
+		})
+		return c.fresh()
+	case *ast.AssignExpression:
+		return c.inferAssign(expr)
+	}
+	c.report(ast.Pos(expr), "cannot check %s, expressions like it aren't supported", expr)
+	return c.fresh()
+}
+
+func (c *checker) inferInfix(expr *ast.InfixExpression) Type {
+	left, right := c.infer(expr.Left), c.infer(expr.Right)
+	ints := func() {
+		c.operand(expr, expr.Left, left, Int)
+		c.operand(expr, expr.Right, right, Int)
+	}
+
+	switch expr.Operator {
+	case token.PLUS:
+		// ints are added, and strings concatenated
+		if err := unify(left, right); err != nil {
+			c.report(ast.Pos(expr), "mismatched types %s and %s in %s", left, right, expr)
+			return c.fresh()
+		}
+		if prune(left) == String {
+			return String
+		}
+		ints()
+		return Int
+	case token.MINUS, token.ASTERISK, token.SLASH:
+		ints()
+		return Int
+	case token.LT, token.GT:
+		ints()
+		return Bool
+	default:
+		// == and != compare values of the same type
+		if err := unify(left, right); err != nil {
+			c.report(ast.Pos(expr), "mismatched types %s and %s in %s", left, right, expr)
+		}
+		return Bool
+	}
+}
+
+// operand reports if operand, of type t, of infix isn't of type want.
+func (c *checker) operand(infix *ast.InfixExpression, operand ast.Expression, t, want Type) {
+	if err := unify(t, want); err != nil {
+		c.report(ast.Pos(operand), "cannot use %s of type %s as %s in %s", operand, t, want, infix)
+	}
+}
+
+// inferIf returns the type of an if expression. When its value is used, both
+// branches must have the same type.
+func (c *checker) inferIf(expr *ast.IfExpression, used bool) Type {
+	// any value can be a condition, by its truthiness
+	c.infer(expr.Condition)
+	_, cons := c.blockValue(expr.Consequence)
+	if expr.Alternative == nil {
+		// the value is null when the condition is false
+		return c.fresh()
+	}
+	_, alt := c.blockValue(expr.Alternative)
+
+	if !used {
+		return c.fresh()
+	}
+	if err := unify(cons, alt); err != nil {
+		c.report(ast.Pos(expr), "mismatched types %s and %s in if branches", cons, alt)
+	}
+	return cons
+}
+
+// inferFunction returns the type of a function literal. If self isn't nil,
+// it is unified with the function's type before its body is checked.
+func (c *checker) inferFunction(expr *ast.FunctionLiteral, self Type) Type {
+	defer c.leave(len(c.declared))
+
+	f := &Func{}
+	for i, param := range expr.Parameters {
+		var t Type = c.fresh()
+		if expr.Types[i] != nil {
+			t = c.annotation(expr.Types[i])
+		}
+		c.bind(param, t)
+		f.Params = append(f.Params, t)
+	}
+	f.Result = c.fresh()
+	if expr.Result != nil {
+		f.Result = c.annotation(expr.Result)
+	}
+	if self != nil {
+		// self is fresh, so this can't fail
+		unify(self, f)
+	}
+
+	outer := c.result
+	c.result = f.Result
+	defer func() { c.result = outer }()
+
+	if last, t := c.blockValue(expr.Body); last != nil {
+		c.returns(last, t)
+	}
+	return f
+}
+
+// checkMacro checks a macro's body. Its parameters, and result are code, which
+// isn't typed.
+func (c *checker) checkMacro(expr *ast.MacroLiteral) {
+	defer c.leave(len(c.declared))
+
+	for _, param := range expr.Parameters {
+		c.bind(param, c.fresh())
+	}
+	outer := c.result
+	c.result = c.fresh()
+	defer func() { c.result = outer }()
+
+	c.blockValue(expr.Body)
+}
+
+func (c *checker) inferCall(expr *ast.CallExpression) Type {
+	fn := c.infer(expr.Function)
+	var args []Type
+	for _, arg := range expr.Arguments {
+		args = append(args, c.infer(arg))
+	}
+
+	switch f := prune(fn).(type) {
+	case *Var:
+		result := c.fresh()
+		if err := unify(f, &Func{Params: args, Result: result}); err != nil {
+			c.report(ast.Pos(expr), "cannot call %s: %s", expr.Function, err)
+		}
+		return result
+	case *Func:
+		if len(args) != len(f.Params) {
+			c.report(ast.Pos(expr), "have %d arguments calling %s of type %s, want %d", len(args), expr.Function, f, len(f.Params))
+			return f.Result
+		}
+		for i, arg := range expr.Arguments {
+			if err := unify(f.Params[i], args[i]); err != nil {
+				c.report(ast.Pos(arg), "cannot use %s of type %s as %s calling %s", arg, args[i], f.Params[i], expr.Function)
+			}
+		}
+		return f.Result
+	}
+	c.report(ast.Pos(expr), "cannot call %s of type %s", expr.Function, fn)
+	return c.fresh()
+}
+
+func (c *checker) inferAssign(expr *ast.AssignExpression) Type {
+	val := c.infer(expr.Value)
+	s, ok := c.env[c.decls[expr.Name]]
+	if !ok {
+		return val
+	}
+	// const variables aren't assignable, and are reported by the resolver
+	target := s.typ
+
+	want := target
+	switch {
+	case expr.Operator == "=":
+	case expr.Operator == "+=" && prune(target) == String:
+		// strings are concatenated
+	default:
+		want = Int
+		if err := unify(target, Int); err != nil {
+			c.report(ast.Pos(expr), "cannot use %s on %s of type %s", expr.Operator, expr.Name, target)
+			return target
+		}
+	}
+
+	if err := unify(want, val); err != nil {
+		c.report(ast.Pos(expr.Value), "cannot assign %s to %s of type %s", val, expr.Name, target)
+	}
+	return target
+}
+
+// instantiate returns s' type, with fresh variables for its generic ones
+func (c *checker) instantiate(s *scheme) Type {
+	if len(s.vars) == 0 {
+		return s.typ
+	}
+	subst := map[*Var]Type{}
+	for _, v := range s.vars {
+		subst[v] = c.fresh()
+	}
+	return substitute(s.typ, subst)
+}
+
+// generalize returns a scheme of t, generic in the variables that aren't
+// bound in the environment.
+func (c *checker) generalize(t Type) *scheme {
+	bound := map[*Var]bool{}
+	for _, s := range c.env {
+		for _, v := range freeVars(s.typ, nil) {
+			// a scheme's own generic vars aren't bound by it, but can be
+			// by other schemes
+			if !s.generic(v) {
+				bound[v] = true
+			}
+		}
+	}
+
+	var vars []*Var
+	for _, v := range freeVars(t, nil) {
+		if !bound[v] {
+			vars = append(vars, v)
+		}
+	}
+	return &scheme{vars: vars, typ: t}
+}
+
+func substitute(t Type, subst map[*Var]Type) Type {
+	switch t := prune(t).(type) {
+	case *Var:
+		if s, ok := subst[t]; ok {
+			return s
+		}
+		return t
+	case *Array:
+		return &Array{Elem: substitute(t.Elem, subst)}
+	case *Hash:
+		return &Hash{Key: substitute(t.Key, subst), Value: substitute(t.Value, subst)}
+	case *Func:
+		f := &Func{Result: substitute(t.Result, subst)}
+		for _, p := range t.Params {
+			f.Params = append(f.Params, substitute(p, subst))
+		}
+		return f
+	default:
+		return t
+	}
+}
+
+// freeVars appends the unknown variables in t to vars, once each
+func freeVars(t Type, vars []*Var) []*Var {
+	switch t := prune(t).(type) {
+	case *Var:
+		for _, v := range vars {
+			if v == t {
+				return vars
+			}
+		}
+		return append(vars, t)
+	case *Array:
+		return freeVars(t.Elem, vars)
+	case *Hash:
+		return freeVars(t.Value, freeVars(t.Key, vars))
+	case *Func:
+		for _, p := range t.Params {
+			vars = freeVars(p, vars)
+		}
+		return freeVars(t.Result, vars)
+	}
+	return vars
+}
+
+// unify makes a, and b the same type by instantiating their variables, or
+// returns an error if they can't be.
+func unify(a, b Type) error {
+	a, b = prune(a), prune(b)
+
+	if v, ok := a.(*Var); ok {
+		if a == b {
+			return nil
+		}
+		if occurs(v, b) {
+			return fmt.Errorf("%s is infinite", newPrinter().print(a))
+		}
+		v.instance = b
+		return nil
+	}
+	if _, ok := b.(*Var); ok {
+		return unify(b, a)
+	}
+
+	mismatch := fmt.Errorf("cannot use %s as %s", a, b)
+	switch a := a.(type) {
+	case Basic:
+		if a != b {
+			return mismatch
+		}
+	case *Array:
+		b, ok := b.(*Array)
+		if !ok {
+			return mismatch
+		}
+		return unify(a.Elem, b.Elem)
+	case *Hash:
+		b, ok := b.(*Hash)
+		if !ok {
+			return mismatch
+		}
+		if err := unify(a.Key, b.Key); err != nil {
+			return err
+		}
+		return unify(a.Value, b.Value)
+	case *Func:
+		b, ok := b.(*Func)
+		if !ok || len(a.Params) != len(b.Params) {
+			return mismatch
+		}
+		for i := range a.Params {
+			if err := unify(a.Params[i], b.Params[i]); err != nil {
+				return err
+			}
+		}
+		return unify(a.Result, b.Result)
+	}
+	return nil
+}
+
+// occurs is true if v is in t, so binding v to t would make an infinite type
+func occurs(v *Var, t Type) bool {
+	for _, free := range freeVars(t, nil) {
+		if free == v {
+			return true
+		}
+	}
+	return false
+}
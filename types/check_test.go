@@ -0,0 +1,274 @@
+package types_test
+
+import (
+	"monkey/ast"
+	"monkey/lexer"
+	"monkey/parser"
+	"monkey/token"
+	"monkey/types"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	input := `
+let one = 1;
+let name = "monkey";
+let not = !name;
+let neg = -name;
+name += "!";
+one -= "1";
+name -= 1;
+for (x in one) { x; }
+try { throw 1; } catch (e) { let caught = e; }
+`
+	want := []string{
+		"5:11: cannot use -name of type string as int",
+		"7:8: cannot assign string to one of type int",
+		"8:1: cannot use -= on name of type string",
+		"9:11: cannot iterate over int",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typs, diags := types.Check(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+
+	wantTypes := map[string]string{
+		"one":    "int",
+		"name":   "string",
+		"not":    "bool",
+		"neg":    "int",
+		"caught": "'a",
+	}
+	ast.Inspect(prog, func(n ast.Node) bool {
+		let, ok := n.(*ast.LetStatement)
+		if !ok {
+			return true
+		}
+		if have := typs[let.Name].String(); have != wantTypes[let.Name.Value] {
+			t.Fatalf("have %s of type %s, want %s", let.Name.Value, have, wantTypes[let.Name.Value])
+		}
+		return true
+	})
+}
+
+func TestCheckAnnotations(t *testing.T) {
+	input := `
+let count: int = 1;
+let name: string = count;
+let list: [int] = "a";
+let odd: float = 1;
+let f: fn(int) -> [bool] = f;
+const copy: bool = !count;
+`
+	want := []string{
+		"3:20: cannot use count of type int as string",
+		"4:19: cannot use \"a\" of type string as [int]",
+		"5:10: unknown type float",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typs, diags := types.Check(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+
+	wantTypes := []string{"int", "string", "[int]", "int", "fn(int) -> [bool]", "bool"}
+	for i, stmt := range prog.Statements {
+		let := stmt.(*ast.LetStatement)
+		if have := typs[let.Name].String(); have != wantTypes[i] {
+			t.Fatalf("have %s of type %s, want %s", let.Name.Value, have, wantTypes[i])
+		}
+	}
+}
+
+func TestCheckExpressions(t *testing.T) {
+	input := `
+let x: int = true;
+let sum = 5 + true;
+let name = "a" + "b";
+let less = 1 < "2";
+let same = x == name;
+let pick = if (x > 1) { x } else { "none" };
+if (less) { x } else { name };
+let maybe = if (same) { x };
+`
+	want := []string{
+		"2:14: cannot use true of type bool as int",
+		"3:11: mismatched types int and bool in (5 + true)",
+		"5:16: cannot use \"2\" of type string as int in (1 < \"2\")",
+		"6:12: mismatched types int and string in (x == name)",
+		"7:12: mismatched types int and string in if branches",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typs, diags := types.Check(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+
+	wantTypes := map[string]string{
+		"x":     "int",
+		"sum":   "'a",
+		"name":  "string",
+		"less":  "bool",
+		"same":  "bool",
+		"pick":  "int",
+		"maybe": "'a",
+	}
+	for _, stmt := range prog.Statements {
+		let, ok := stmt.(*ast.LetStatement)
+		if !ok {
+			continue
+		}
+		if have := typs[let.Name].String(); have != wantTypes[let.Name.Value] {
+			t.Fatalf("have %s of type %s, want %s", let.Name.Value, have, wantTypes[let.Name.Value])
+		}
+	}
+}
+
+func TestCheckFunctions(t *testing.T) {
+	input := `
+const id = fn(x) { x };
+let a: int = id(1);
+let b: string = id("s");
+let mono = fn(x) { x };
+let c: int = mono(1);
+mono = fn(y) { y };
+let d = mono("s");
+let poly = fn(x) { x }; poly(1); poly(true);
+const pair = fn(x) { const k = fn(y) { x }; k };
+let e: bool = pair(true)(1);
+let f: bool = pair(1)("s");
+let add = fn(n: int, m) -> string { if (n > m) { return "more"; }; n + m };
+let g = add(1);
+let h = 1(2);
+let inc = add(_, 1) |> fn(p) { p(2) };
+`
+	want := []string{
+		"8:14: cannot use \"s\" of type string as int calling mono",
+		"12:15: cannot use pair(1)(\"s\") of type int as bool",
+		"13:68: cannot return (n + m) of type int as string",
+		"14:9: have 1 arguments calling add of type fn(int, int) -> string, want 2",
+		"15:9: cannot call 1 of type int",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typs, diags := types.Check(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+
+	wantTypes := map[string]string{
+		"id":   "fn('a) -> 'a",
+		"mono": "fn(int) -> int",
+		"poly": "fn('a) -> 'a",
+		"pair": "fn('a) -> fn('b) -> 'a",
+		"add":  "fn(int, int) -> string",
+		"inc":  "string",
+	}
+	for _, stmt := range prog.Statements {
+		let, ok := stmt.(*ast.LetStatement)
+		if !ok {
+			continue
+		}
+		if want, ok := wantTypes[let.Name.Value]; ok {
+			if have := typs[let.Name].String(); have != want {
+				t.Fatalf("have %s of type %s, want %s", let.Name.Value, have, want)
+			}
+		}
+	}
+}
+
+// Programs built without the parser can have nodes it never makes.
+func TestCheckMissingValues(t *testing.T) {
+	pos := token.Position{Line: 1, Column: 1}
+	prog := &ast.Program{Statements: []ast.Statement{
+		&ast.LetStatement{Token: token.Token{Type: token.LET, Literal: "let", Pos: pos}, Name: &ast.Identifier{Value: "x"}},
+		&ast.ExpressionStatement{Expression: &ast.NamedType{Token: token.Token{Type: token.IDENT, Literal: "int", Pos: pos}, Name: "int"}},
+	}}
+	want := []string{
+		"1:1: missing value in let statement",
+		"1:1: cannot check int, expressions like it aren't supported",
+	}
+
+	_, diags := types.Check(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+}
+
+func TestCheckRecursion(t *testing.T) {
+	input := `
+let fact = fn(n) { if (n < 2) { 1 } else { n * fact(n - 1) } };
+let bad = fn(n: int) -> int { bad("x") };
+bad(1);
+let r: string = fact(3);
+`
+	want := []string{
+		"3:35: cannot use \"x\" of type string as int calling bad",
+		"5:17: cannot use fact(3) of type int as string",
+	}
+
+	prog, err := parser.New(lexer.New(input)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typs, diags := types.Check(prog)
+	if len(diags) != len(want) {
+		t.Fatalf("have %v diagnostics %v, want %v", len(diags), diags, len(want))
+	}
+	for i, d := range diags {
+		if d.String() != want[i] {
+			t.Fatalf("have diagnostic %s, want %s", d, want[i])
+		}
+	}
+
+	fact := prog.Statements[0].(*ast.LetStatement)
+	if have, want := typs[fact.Name].String(), "fn(int) -> int"; have != want {
+		t.Fatalf("have fact of type %s, want %s", have, want)
+	}
+}
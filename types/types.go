@@ -0,0 +1,122 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type is the type of a Monkey value.
+type Type interface {
+	String() string
+}
+
+// Basic is a type without parameters.
+type Basic string
+
+const (
+	// Int is a 64 bit integer
+	Int Basic = "int"
+	// Bool is true, or false
+	Bool Basic = "bool"
+	// String is text
+	String Basic = "string"
+	// Module is an imported module
+	Module Basic = "module"
+)
+
+func (b Basic) String() string {
+	return string(b)
+}
+
+// Array is a list of Elem: [int]
+type Array struct {
+	Elem Type
+}
+
+func (a *Array) String() string {
+	return newPrinter().print(a)
+}
+
+// Hash maps Key to Value: {string: int}
+type Hash struct {
+	Key   Type
+	Value Type
+}
+
+func (h *Hash) String() string {
+	return newPrinter().print(h)
+}
+
+// Func is a function: fn(int, string) -> bool
+type Func struct {
+	Params []Type
+	Result Type
+}
+
+func (f *Func) String() string {
+	return newPrinter().print(f)
+}
+
+// Var is a type that isn't known yet. It becomes its instance once it is
+// unified with another type.
+type Var struct {
+	id       int
+	instance Type
+}
+
+func (v *Var) String() string {
+	return newPrinter().print(v)
+}
+
+// prune returns t, or the type its variables were instantiated to
+func prune(t Type) Type {
+	if v, ok := t.(*Var); ok && v.instance != nil {
+		v.instance = prune(v.instance)
+		return v.instance
+	}
+	return t
+}
+
+// printer names type variables 'a, 'b, ... in the order they're printed
+type printer struct {
+	names map[*Var]string
+}
+
+func newPrinter() *printer {
+	return &printer{names: map[*Var]string{}}
+}
+
+func (p *printer) print(t Type) string {
+	switch t := prune(t).(type) {
+	case *Var:
+		name, ok := p.names[t]
+		if !ok {
+			name = varName(len(p.names))
+			p.names[t] = name
+		}
+		return name
+	case *Array:
+		return "[" + p.print(t.Elem) + "]"
+	case *Hash:
+		return "{" + p.print(t.Key) + ": " + p.print(t.Value) + "}"
+	case *Func:
+		var params []string
+		for _, param := range t.Params {
+			params = append(params, p.print(param))
+		}
+		return "fn(" + strings.Join(params, ", ") + ") -> " + p.print(t.Result)
+	case nil:
+		return "<nil>"
+	default:
+		return t.String()
+	}
+}
+
+// varName returns 'a for 0, 'z for 25, 'a1 for 26, ...
+func varName(i int) string {
+	name := "'" + string(rune('a'+i%26))
+	if i >= 26 {
+		name += fmt.Sprint(i / 26)
+	}
+	return name
+}
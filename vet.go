@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"monkey/lint"
+	"monkey/module"
+	"monkey/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileFinding is a lint finding in a file, as printed by vet -json
+type fileFinding struct {
+	File string `json:"file"`
+	lint.Finding
+}
+
+// vet lints the files in args, printing findings to stdout. It returns the
+// process' exit code: 1 if anything was found, 2 if vet couldn't run.
+func vet(args []string) int {
+	fs := flag.NewFlagSet("vet", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print findings as a JSON array")
+	ruleNames := fs.String("rules", "", "comma separated rules to run, instead of the default ones")
+	list := fs.Bool("list", false, "list the available rules, and exit")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: monkey vet [flags] file...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *list {
+		for _, r := range lint.Rules() {
+			fmt.Printf("%-20s %-8s %s\n", r.Name, r.Severity, r.Doc)
+		}
+		return 0
+	}
+
+	rules := lint.DefaultRules()
+	if *ruleNames != "" {
+		rules = nil
+		for _, name := range strings.Split(*ruleNames, ",") {
+			r := lint.Lookup(strings.TrimSpace(name))
+			if r == nil {
+				fmt.Fprintf(os.Stderr, "unknown rule %q\n", name)
+				return 2
+			}
+			rules = append(rules, r)
+		}
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	// imports not found next to the importing file are searched for in
+	// MONKEYPATH's directories
+	loader := module.NewLoader(filepath.SplitList(os.Getenv("MONKEYPATH"))...)
+
+	findings := []fileFinding{}
+	for _, file := range fs.Args() {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed reading %s: %s\n", file, err)
+			return 2
+		}
+		for _, f := range lint.Lint(string(b), rules) {
+			findings = append(findings, fileFinding{File: file, Finding: f})
+		}
+		if f, ok := importFinding(loader, file); ok {
+			findings = append(findings, f)
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			fmt.Fprintf(os.Stderr, "failed encoding findings: %s\n", err)
+			return 2
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Printf("%s:%s\n", f.File, f.Finding)
+		}
+	}
+
+	if len(findings) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// importFinding loads file's imports, returning a finding if any of them
+// can't be found, don't parse, or import each other in a cycle.
+func importFinding(loader *module.Loader, file string) (fileFinding, bool) {
+	_, err := loader.Load(file)
+	if err == nil {
+		return fileFinding{}, false
+	}
+
+	e := err.(*module.Error)
+	abs, _ := filepath.Abs(file)
+	if e.File == abs || e.File == file {
+		if e.Pos == (token.Position{}) {
+			// file's own parse errors were already reported by lint
+			return fileFinding{}, false
+		}
+		e.File = file
+	}
+	return fileFinding{File: e.File, Finding: lint.Finding{Rule: "import", Severity: lint.Error, Pos: e.Pos, Msg: e.Err.Error()}}, true
+}